@@ -1,11 +1,13 @@
 package gol
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
-	_ "github.com/go-sql-driver/mysql"
-	_ "github.com/lib/pq"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"time"
 )
 
 const (
@@ -26,67 +28,111 @@ type DB struct {
 	modeLog          bool
 	modeResultKey    int
 	modeTest         bool
+	modeTestTx       bool
+	modeRetryCount   int
+	modeRetryBackoff time.Duration
+	savepoint        string
+	savepointLevel   int
+	tlsConfigName    string
 }
 
 func (rec *DB) Init(databaseType string, host string, port string, user string, pass string, database string, optionMap map[string]string) error {
+	option, err := makeOption(optionMap)
+	if err != nil {
+		return err
+	}
+
+	err = rec.InitOption(databaseType, host, port, user, pass, database, option)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *DB) InitOption(databaseType string, host string, port string, user string, pass string, database string, option *OptionType) error {
+	var err error
+
+	if option == nil {
+		option = &OptionType{}
+	}
+
+	dialect, err := GetDialect(databaseType)
+	if err != nil {
+		return err
+	}
+
+	tlsConfigName, err := registerMysqlTLSConfig(dialect, host, option)
+	if err != nil {
+		return err
+	}
+	if tlsConfigName != "" {
+		optionData := *option
+		optionData.tlsConfigName = tlsConfigName
+		option = &optionData
+	}
+
+	source, err := dialect.DataSourceName(host, port, user, pass, database, option)
+	if err != nil {
+		deregisterMysqlTLSConfig(tlsConfigName)
+		return err
+	}
+
+	err = rec.InitDSN(databaseType, source, option)
+	if err != nil {
+		deregisterMysqlTLSConfig(tlsConfigName)
+		return err
+	}
+
+	rec.tlsConfigName = tlsConfigName
+
+	return nil
+}
+
+func (rec *DB) InitDSN(databaseType string, source string, option *OptionType) error {
 	var err error
 	var db *sql.DB
 
-	switch databaseType {
-	case DatabaseTypeMysql:
-		// [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
-		source := fmt.Sprintf(
-			"%s:%s@tcp(%s:%s)/%s",
-			user,
-			pass,
-			host,
-			port,
-			database,
-		)
-
-		db, err = sql.Open(DatabaseTypeMysql, source)
-		if err != nil {
-			return err
-		}
+	if option == nil {
+		option = &OptionType{}
+	}
 
-		rec.modeDatabaseType = DatabaseTypeMysql
-	case DatabaseTypePostgresql:
-
-		sslMode := DatabaseSslModeDisable
-		if v, ok := optionMap["sslMode"]; ok {
-			switch v {
-			case DatabaseSslModeDisable:
-				sslMode = DatabaseSslModeDisable
-			case DatabaseSslModeRequire:
-				sslMode = DatabaseSslModeRequire
-			case DatabaseSslModeVerifyCa:
-				sslMode = DatabaseSslModeVerifyCa
-			case DatabaseSslModeVerifyFull:
-				sslMode = DatabaseSslModeVerifyFull
-			}
-		}
+	dialect, err := GetDialect(databaseType)
+	if err != nil {
+		return err
+	}
+
+	db, err = sql.Open(dialect.DriverName(), source)
+	if err != nil {
+		return err
+	}
+
+	if option.MaxOpenConns != 0 {
+		db.SetMaxOpenConns(option.MaxOpenConns)
+	}
+	if option.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(option.MaxIdleConns)
+	}
+	if option.ConnMaxLifetime != 0 {
+		db.SetConnMaxLifetime(option.ConnMaxLifetime)
+	}
+	if option.ConnMaxIdleTime != 0 {
+		db.SetConnMaxIdleTime(option.ConnMaxIdleTime)
+	}
 
-		source := fmt.Sprintf(
-			"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-			host,
-			port,
-			user,
-			pass,
-			database,
-			sslMode,
-		)
-
-		db, err = sql.Open(DatabaseTypePostgresql, source)
+	if option.PingTimeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), option.PingTimeout)
+		defer cancel()
+
+		err = db.PingContext(ctx)
 		if err != nil {
+			_ = db.Close()
 			return err
 		}
-
-		rec.modeDatabaseType = DatabaseTypePostgresql
-	default:
-		return errors.New("unknown databaseType")
 	}
 
 	rec.DB = db
+	rec.modeDatabaseType = databaseType
 	rec.SetModeResultKey()
 	rec.SetModeLog(false)
 
@@ -97,6 +143,13 @@ func (rec *DB) SetModeLog(mode bool) {
 	rec.modeLog = mode
 }
 
+// SetModeRetry sets how many times Transaction retries on a serialization failure or a deadlock.
+// The wait before the n-th retry is backoff * 2^(n-1).
+func (rec *DB) SetModeRetry(count int, backoff time.Duration) {
+	rec.modeRetryCount = count
+	rec.modeRetryBackoff = backoff
+}
+
 func (rec *DB) SetModeResultKey() {
 	rec.modeResultKey = resultKeyModeNone
 }
@@ -128,9 +181,18 @@ func (rec *DB) Query() *QueryType {
 }
 
 func (rec *DB) Exec(query string, valueList ...interface{}) (sql.Result, error) {
+	result, err := rec.ExecContext(context.Background(), query, valueList...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rec *DB) ExecContext(ctx context.Context, query string, valueList ...interface{}) (sql.Result, error) {
 	queryData := rec.Query()
 
-	result, err := queryData.Exec(query, valueList...)
+	result, err := queryData.ExecContext(ctx, query, valueList...)
 	if err != nil {
 		return nil, err
 	}
@@ -139,9 +201,18 @@ func (rec *DB) Exec(query string, valueList ...interface{}) (sql.Result, error)
 }
 
 func (rec *DB) ExecQuery(dest interface{}, query string, valueList ...interface{}) error {
+	err := rec.ExecQueryContext(context.Background(), dest, query, valueList...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *DB) ExecQueryContext(ctx context.Context, dest interface{}, query string, valueList ...interface{}) error {
 	queryData := rec.Query()
 
-	err := queryData.ExecQuery(dest, query, valueList...)
+	err := queryData.ExecQueryContext(ctx, dest, query, valueList...)
 	if err != nil {
 		return err
 	}
@@ -166,19 +237,63 @@ func (rec *DB) Close() error {
 	rec.DB = nil
 	rec.TX = nil
 
+	deregisterMysqlTLSConfig(rec.tlsConfigName)
+	rec.tlsConfigName = ""
+
 	return nil
 }
 
 func (rec *DB) Begin() (*DB, error) {
+	tx, err := rec.BeginContext(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (rec *DB) BeginContext(ctx context.Context) (*DB, error) {
+	tx, err := rec.BeginTx(ctx, sql.LevelDefault, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (rec *DB) BeginTx(ctx context.Context, isolation sql.IsolationLevel, readOnly bool) (*DB, error) {
 	if rec.modeTest {
 		return rec, nil
 	}
 
 	if rec.TX != nil {
-		return nil, errors.New("exist transaction")
+		if isolation != sql.LevelDefault || readOnly {
+			return nil, errors.New("transaction option can not be used in nested transaction")
+		}
+
+		// nested transaction is a savepoint
+		level := rec.savepointLevel + 1
+		savepoint := fmt.Sprintf("gol_savepoint_%v", level)
+
+		_, err := rec.TX.ExecContext(ctx, fmt.Sprintf("SAVEPOINT %s", savepoint))
+		if err != nil {
+			return nil, err
+		}
+
+		queryData := *rec
+		queryData.modeTestTx = false
+		queryData.savepoint = savepoint
+		queryData.savepointLevel = level
+
+		return &queryData, nil
+	}
+
+	option := &sql.TxOptions{
+		Isolation: isolation,
+		ReadOnly:  readOnly,
 	}
 
-	tx, err := rec.DB.Begin()
+	tx, err := rec.DB.BeginTx(ctx, option)
 	if err != nil {
 		return nil, err
 	}
@@ -192,7 +307,7 @@ func (rec *DB) Begin() (*DB, error) {
 func (rec *DB) Commit() error {
 	var err error
 
-	if rec.modeTest {
+	if rec.modeTest || rec.modeTestTx {
 		return nil
 	}
 
@@ -200,7 +315,11 @@ func (rec *DB) Commit() error {
 		return nil
 	}
 
-	err = rec.TX.Commit()
+	if rec.savepoint != "" {
+		_, err = rec.TX.ExecContext(context.Background(), fmt.Sprintf("RELEASE SAVEPOINT %s", rec.savepoint))
+	} else {
+		err = rec.TX.Commit()
+	}
 	if err != nil {
 		return err
 	}
@@ -211,17 +330,34 @@ func (rec *DB) Commit() error {
 }
 
 func (rec *DB) Rollback() error {
-	var err error
-
-	if rec.modeTest {
+	if rec.modeTest || rec.modeTestTx {
 		return nil
 	}
 
+	err := rec.rollback()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *DB) rollback() error {
+	var err error
+
 	if rec.TX == nil {
 		return nil
 	}
 
-	err = rec.TX.Rollback()
+	if rec.savepoint != "" {
+		// ROLLBACK TO keeps the savepoint, so release it as Commit
+		_, err = rec.TX.ExecContext(context.Background(), fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", rec.savepoint))
+		if err == nil {
+			_, err = rec.TX.ExecContext(context.Background(), fmt.Sprintf("RELEASE SAVEPOINT %s", rec.savepoint))
+		}
+	} else {
+		err = rec.TX.Rollback()
+	}
 	if err != nil {
 		return err
 	}
@@ -231,6 +367,91 @@ func (rec *DB) Rollback() error {
 	return nil
 }
 
+// Transaction commits when fn returns nil, and rolls back when fn returns an error or panics.
+// On a transaction it works with a savepoint and does not retry.
+func (rec *DB) Transaction(fn func(tx *DB) error) error {
+	err := rec.TransactionContext(context.Background(), fn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *DB) TransactionContext(ctx context.Context, fn func(tx *DB) error) error {
+	retryCount := rec.modeRetryCount
+	if rec.TX != nil {
+		retryCount = 0
+	}
+
+	for i := 0; ; i++ {
+		err := rec.transaction(ctx, fn)
+		if err == nil {
+			return nil
+		}
+
+		if i >= retryCount || !isRetryError(err) {
+			return err
+		}
+
+		timer := time.NewTimer(rec.modeRetryBackoff * time.Duration(1<<uint(i)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (rec *DB) transaction(ctx context.Context, fn func(tx *DB) error) error {
+	tx, err := rec.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	err = fn(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// isRetryError reports whether err is a serialization failure or a deadlock.
+func isRetryError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", "40P01":
+			return true
+		}
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1213:
+			return true
+		}
+	}
+
+	return false
+}
+
 func (rec *DB) TestStart() {
 	if rec.DB == nil {
 		return
@@ -254,3 +475,33 @@ func (rec *DB) TestEnd() {
 	_ = rec.DB.Close()
 	rec.DB = nil
 }
+
+// TestingT is the part of testing.TB used by TestTx, so that the testing package is not imported.
+type TestingT interface {
+	Helper()
+	Fatal(args ...interface{})
+	Error(args ...interface{})
+	Cleanup(f func())
+}
+
+// TestTx begins a transaction, or a savepoint on a transaction, that is rolled back when the test ends.
+// Commit and Rollback of the returned DB do nothing, and Begin on it makes a savepoint.
+// The connection pool is left open for the next test.
+func (rec *DB) TestTx(t TestingT) *DB {
+	t.Helper()
+
+	tx, err := rec.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.modeTestTx = true
+
+	t.Cleanup(func() {
+		err := tx.rollback()
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	return tx
+}
@@ -0,0 +1,1996 @@
+package gol
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testOpenSqlite(t *testing.T) *DB {
+	database := strings.Replace(t.Name(), "/", "_", -1)
+
+	db, err := Open(DatabaseTypeSqlite, "", "", "", "", database, map[string]string{"mode": "memory"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	queryList := []string{
+		`CREATE TABLE "test_item" ("id" INTEGER PRIMARY KEY AUTOINCREMENT, "created_at" DATETIME NOT NULL, "created_by" INTEGER, "updated_at" DATETIME NOT NULL, "updated_by" INTEGER, "deleted_at" DATETIME, "deleted_by" INTEGER, "name" INTEGER NOT NULL, "user_id" INTEGER NOT NULL)`,
+		`CREATE TABLE "test_user" ("id" INTEGER PRIMARY KEY AUTOINCREMENT, "created_at" DATETIME NOT NULL, "created_by" INTEGER, "updated_at" DATETIME NOT NULL, "updated_by" INTEGER, "deleted_at" DATETIME, "deleted_by" INTEGER, "name" TEXT NOT NULL, "pass" TEXT NOT NULL)`,
+	}
+	for _, query := range queryList {
+		_, err = db.Exec(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return db
+}
+
+func testInsertItem(t *testing.T, db *DB, name int, userId int) {
+	now := time.Now()
+	testItemTable := TestItem{}
+
+	query := db.Query()
+	query.SetTable(&testItemTable)
+	query.SetValuesColumn(
+		&testItemTable.CreatedAt,
+		&testItemTable.UpdatedAt,
+		&testItemTable.Name,
+		&testItemTable.UserId,
+	)
+	query.SetValues(now, now, name, userId)
+	_, err := query.Insert()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDB_Sqlite(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 10)
+		testInsertItem(t, db, 3, 20)
+
+		var resultList []TestItem
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelectAll(&testItemTable)
+		query.SetWhereIs(&testItemTable.UserId, 10)
+		query.SetOrderByDesc(&testItemTable.Name)
+		err := query.Select(&resultList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			var nameList []int
+			for _, result := range resultList {
+				nameList = append(nameList, result.Name)
+			}
+			target := fmt.Sprintf("%v", nameList)
+
+			check := `[2 1]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success memory", func(t *testing.T) {
+		var dbList []*DB
+		for i := 0; i < 2; i++ {
+			db, err := Open(DatabaseTypeSqlite, "", "", "", "", ":memory:", map[string]string{"maxOpenConns": "2"})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer func() {
+				_ = db.Close()
+			}()
+
+			dbList = append(dbList, db)
+		}
+
+		_, err := dbList[0].Exec(`CREATE TABLE "test_memory" ("id" INTEGER)`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		// the connections of the pool share the database
+		tx, err := dbList[0].Begin()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+
+		_, err = dbList[0].Exec(`INSERT INTO "test_memory" ("id") VALUES (1)`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		// the other Open does not
+		_, err = dbList[1].Exec(`INSERT INTO "test_memory" ("id") VALUES (1)`)
+
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `no such table: test_memory`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestDB_Init(t *testing.T) {
+	t.Run("success pool", func(t *testing.T) {
+		db := &DB{}
+		err := db.Init(DatabaseTypeSqlite, "", "", "", "", ":memory:", map[string]string{
+			"maxOpenConns":    "3",
+			"maxIdleConns":    "-1",
+			"connMaxLifetime": "5m",
+			"connMaxIdleTime": "30s",
+			"pingTimeout":     "3s",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = db.Close()
+		}()
+
+		_, err = db.Exec("SELECT 1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			stats := db.DB.Stats()
+			target := fmt.Sprintf("%v %v", stats.MaxOpenConnections, stats.Idle)
+
+			check := `3 0`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error ping", func(t *testing.T) {
+		db := &DB{}
+		err := db.Init(DatabaseTypePostgresql, "127.0.0.1", "1", "user", "pass", "database", map[string]string{
+			"pingTimeout": "3s",
+		})
+
+		{
+			target := fmt.Sprintf("%v %v", err != nil, db.DB == nil)
+
+			check := `true true`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestDB_Context(t *testing.T) {
+	t.Run("error canceled", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, execErr := db.ExecContext(ctx, `DELETE FROM "test_item"`)
+
+		var resultList []TestItem
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelectAll(&testItemTable)
+		selectErr := query.SelectContext(ctx, &resultList)
+
+		_, beginErr := db.BeginContext(ctx)
+
+		{
+			target := fmt.Sprintf("%v %v %v", errors.Is(execErr, context.Canceled), errors.Is(selectErr, context.Canceled), errors.Is(beginErr, context.Canceled))
+
+			check := `true true true`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success canceled after begin nest", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		tx, err := db.Begin()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		nestTx, err := tx.BeginContext(ctx)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		testInsertItem(t, nestTx, 1, 10)
+		cancel()
+
+		err = nestTx.Commit()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		rollbackCtx, rollbackCancel := context.WithCancel(context.Background())
+		defer rollbackCancel()
+		nestTx, err = tx.BeginContext(rollbackCtx)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		testInsertItem(t, nestTx, 2, 10)
+		rollbackCancel()
+
+		err = nestTx.Rollback()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, tx)
+
+			check := `[1]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error canceled nest", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		tx, err := db.Begin()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = tx.BeginContext(ctx)
+
+		{
+			target := fmt.Sprintf("%v", errors.Is(err, context.Canceled))
+
+			check := `true`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestDB_CloseTLSConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, err := OpenOption(DatabaseTypeMysql, "localhost", "3306", "user", "pass", "database", &OptionType{TlsConfig: &tls.Config{ServerName: "localhost"}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		source := fmt.Sprintf("user:pass@tcp(localhost:3306)/database?tls=%s", db.tlsConfigName)
+
+		_, err = mysql.ParseDSN(source)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		err = db.Close()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		_, err = mysql.ParseDSN(source)
+
+		{
+			target := fmt.Sprintf("%v %v", strings.HasPrefix(source, "user:pass@tcp(localhost:3306)/database?tls=gol_tls_"), err != nil)
+
+			check := `true true`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error data source name", func(t *testing.T) {
+		_, err := OpenOption(DatabaseTypeMysql, "localhost", "3306", "user", "pass", "database", &OptionType{
+			TlsConfig: &tls.Config{ServerName: "localhost"},
+			ParamMap:  map[string]string{"parseTime": "notbool"},
+		})
+		if err == nil {
+			t.Error("error not exist")
+			return
+		}
+
+		// the registered tls.Config is deregistered
+		_, err = mysql.ParseDSN(fmt.Sprintf("user:pass@tcp(localhost:3306)/database?tls=%s%v", mysqlTLSConfigPrefix, mysqlTLSConfigCount))
+
+		{
+			target := fmt.Sprintf("%v", err != nil)
+
+			check := `true`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func testSelectItemNameList(t *testing.T, db *DB) string {
+	var resultList []TestItem
+
+	testItemTable := TestItem{}
+	query := db.Query()
+	query.SetTable(&testItemTable)
+	query.SetSelectAll(&testItemTable)
+	query.SetOrderBy(&testItemTable.Name)
+	err := query.Select(&resultList)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var nameList []int
+	for _, result := range resultList {
+		nameList = append(nameList, result.Name)
+	}
+
+	return fmt.Sprintf("%v", nameList)
+}
+
+func TestDB_BeginNest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		tx, err := db.Begin()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+
+		testInsertItem(t, tx, 1, 10)
+
+		{
+			nestTx, err := tx.Begin()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			testInsertItem(t, nestTx, 2, 10)
+
+			err = nestTx.Rollback()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		{
+			nestTx, err := tx.Begin()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			testInsertItem(t, nestTx, 3, 10)
+
+			err = nestTx.Commit()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[1 3]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success rollback release", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		tx, err := db.Begin()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+
+		for i := 1; i <= 3; i++ {
+			nestTx, err := tx.Begin()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			testInsertItem(t, nestTx, i, 10)
+
+			err = nestTx.Rollback()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		// the savepoint is released by Rollback
+		_, err = tx.Exec("RELEASE SAVEPOINT gol_savepoint_1")
+
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `no such savepoint: gol_savepoint_1`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			nestTx, err := tx.Begin()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			testInsertItem(t, nestTx, 4, 10)
+
+			err = nestTx.Commit()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[4]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestDB_BeginTx(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		tx, err := db.BeginTx(context.Background(), sql.LevelSerializable, false)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+
+		testInsertItem(t, tx, 1, 10)
+
+		err = tx.Commit()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[1]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error nested transaction option", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		tx, err := db.Begin()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+
+		_, err = tx.BeginTx(context.Background(), sql.LevelDefault, true)
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `transaction option can not be used in nested transaction`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestDB_Transaction(t *testing.T) {
+	t.Run("success commit", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		err := db.Transaction(func(tx *DB) error {
+			testInsertItem(t, tx, 1, 10)
+
+			return tx.Transaction(func(tx *DB) error {
+				testInsertItem(t, tx, 2, 10)
+				return nil
+			})
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[1 2]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success rollback", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		err := db.Transaction(func(tx *DB) error {
+			testInsertItem(t, tx, 1, 10)
+			return errors.New("rollback")
+		})
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `rollback`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success panic", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		p := func() (p interface{}) {
+			defer func() {
+				p = recover()
+			}()
+
+			_ = db.Transaction(func(tx *DB) error {
+				testInsertItem(t, tx, 1, 10)
+				panic("panic")
+			})
+
+			return nil
+		}()
+		{
+			target := fmt.Sprintf("%v", p)
+
+			check := `panic`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success retry", func(t *testing.T) {
+		db := testOpenSqlite(t)
+		db.SetModeRetry(2, time.Millisecond)
+
+		count := 0
+		err := db.Transaction(func(tx *DB) error {
+			count++
+			testInsertItem(t, tx, count, 10)
+			if count < 3 {
+				return &pq.Error{Code: "40001"}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[3]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error retry over", func(t *testing.T) {
+		db := testOpenSqlite(t)
+		db.SetModeRetry(1, time.Millisecond)
+
+		count := 0
+		err := db.Transaction(func(tx *DB) error {
+			count++
+			return &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}
+		})
+		if err == nil {
+			t.Error("err is nil")
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v", count)
+
+			check := `2`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestDB_TestTx(t *testing.T) {
+	db := testOpenSqlite(t)
+
+	t.Run("success", func(t *testing.T) {
+		tx := db.TestTx(t)
+
+		testInsertItem(t, tx, 1, 10)
+
+		t.Run("nest", func(t *testing.T) {
+			nestTx := tx.TestTx(t)
+
+			testInsertItem(t, nestTx, 2, 10)
+
+			err := nestTx.Commit()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			{
+				target := testSelectItemNameList(t, nestTx)
+
+				check := `[1 2]`
+
+				if target != check {
+					t.Error("target:", target)
+					t.Error("check :", check)
+					return
+				}
+			}
+		})
+
+		{
+			target := testSelectItemNameList(t, tx)
+
+			check := `[1]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success rollback", func(t *testing.T) {
+		target := testSelectItemNameList(t, db)
+
+		check := `[]`
+
+		if target != check {
+			t.Error("target:", target)
+			t.Error("check :", check)
+			return
+		}
+	})
+}
+
+type testDialectSqliteNoReturning struct {
+	DialectSqlite
+}
+
+func (rec *testDialectSqliteNoReturning) Support(feature int) bool {
+	return false
+}
+
+func TestQueryType_InsertReturning(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+
+		now := time.Now()
+		testItemTable := TestItem{}
+
+		var resultList []TestItem
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.CreatedAt, &testItemTable.UpdatedAt, &testItemTable.Name, &testItemTable.UserId)
+		query.SetValues(now, now, 2, 10)
+		query.SetValues(now, now, 3, 10)
+		query.SetReturning(&testItemTable.Id, &testItemTable.Name)
+		err := query.InsertReturning(&resultList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v", resultList)
+
+			var checkList []TestItem
+			checkList = append(checkList, TestItem{Id: 2, Name: 2})
+			checkList = append(checkList, TestItem{Id: 3, Name: 3})
+			check := fmt.Sprintf("%v", checkList)
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success LastInsertId", func(t *testing.T) {
+		RegisterDialect("test_sqlite_no_returning", &testDialectSqliteNoReturning{})
+
+		db := testOpenSqlite(t)
+		db.modeDatabaseType = "test_sqlite_no_returning"
+
+		testInsertItem(t, db, 1, 10)
+
+		now := time.Now()
+		testItemTable := TestItem{}
+
+		var resultList []map[string]interface{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.CreatedAt, &testItemTable.UpdatedAt, &testItemTable.Name, &testItemTable.UserId)
+		query.SetValues(now, now, 2, 10)
+		query.SetReturning(&testItemTable.Name, &testItemTable.Id)
+		query.SetLastInsertId(&testItemTable.Id)
+		err := query.InsertReturning(&resultList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v", resultList)
+
+			check := `[map[id:2 name:2]]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error LastInsertId not set", func(t *testing.T) {
+		RegisterDialect("test_sqlite_no_returning", &testDialectSqliteNoReturning{})
+
+		db := testOpenSqlite(t)
+		db.modeDatabaseType = "test_sqlite_no_returning"
+
+		now := time.Now()
+		testItemTable := TestItem{}
+
+		var resultList []TestItem
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.CreatedAt, &testItemTable.UpdatedAt, &testItemTable.Name, &testItemTable.UserId)
+		query.SetValues(now, now, 2, 10)
+		query.SetReturning(&testItemTable.CreatedAt, &testItemTable.Id)
+		err := query.InsertReturning(&resultList)
+
+		{
+			target := fmt.Sprintf("%v %v", err, testSelectItemNameList(t, db))
+
+			check := `returning with LastInsertId needs SetLastInsertId []`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error LastInsertId not integer", func(t *testing.T) {
+		RegisterDialect("test_sqlite_no_returning", &testDialectSqliteNoReturning{})
+
+		db := testOpenSqlite(t)
+		db.modeDatabaseType = "test_sqlite_no_returning"
+
+		now := time.Now()
+		testItemTable := TestItem{}
+
+		var resultList []TestItem
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.CreatedAt, &testItemTable.UpdatedAt, &testItemTable.Name, &testItemTable.UserId)
+		query.SetValues(now, now, 2, 10)
+		query.SetReturning(&testItemTable.CreatedAt, &testItemTable.Id)
+		query.SetLastInsertId(&testItemTable.CreatedAt)
+		err := query.InsertReturning(&resultList)
+
+		{
+			target := fmt.Sprintf("%v %v", err, testSelectItemNameList(t, db))
+
+			check := `last insert id column is not integer []`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error LastInsertId values", func(t *testing.T) {
+		RegisterDialect("test_sqlite_no_returning", &testDialectSqliteNoReturning{})
+
+		db := testOpenSqlite(t)
+		db.modeDatabaseType = "test_sqlite_no_returning"
+
+		now := time.Now()
+		testItemTable := TestItem{}
+
+		var resultList []TestItem
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.CreatedAt, &testItemTable.UpdatedAt, &testItemTable.Name, &testItemTable.UserId)
+		query.SetValues(now, now, 2, 10)
+		query.SetValues(now, now, 3, 10)
+		query.SetReturning(&testItemTable.CreatedAt, &testItemTable.Id)
+		query.SetLastInsertId(&testItemTable.Id)
+		err := query.InsertReturning(&resultList)
+
+		{
+			target := fmt.Sprintf("%v %v", err, testSelectItemNameList(t, db))
+
+			check := `returning with LastInsertId needs one values []`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_UpdateReturning(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 20)
+
+		testItemTable := TestItem{}
+
+		var resultList []map[string]interface{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSet(&testItemTable.Name, 3)
+		query.SetWhereIs(&testItemTable.UserId, 20)
+		query.SetReturning(&testItemTable.Id, &testItemTable.Name)
+		err := query.UpdateReturning(&resultList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v", resultList)
+
+			check := `[map[id:2 name:3]]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error returning not exist", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+
+		now := time.Now()
+		testItemTable := TestItem{}
+
+		var resultList []TestItem
+
+		insertQuery := db.Query()
+		insertQuery.SetTable(&testItemTable)
+		insertQuery.SetValuesColumn(&testItemTable.CreatedAt, &testItemTable.UpdatedAt, &testItemTable.Name, &testItemTable.UserId)
+		insertQuery.SetValues(now, now, 2, 10)
+		insertErr := insertQuery.InsertReturning(&resultList)
+
+		updateQuery := db.Query()
+		updateQuery.SetTable(&testItemTable)
+		updateQuery.SetSet(&testItemTable.Name, 3)
+		updateQuery.SetWhereIs(&testItemTable.UserId, 10)
+		updateErr := updateQuery.UpdateReturning(&resultList)
+
+		deleteQuery := db.Query()
+		deleteQuery.SetTable(&testItemTable)
+		deleteQuery.SetWhereIs(&testItemTable.UserId, 10)
+		deleteErr := deleteQuery.DeleteReturning(&resultList)
+
+		{
+			target := fmt.Sprintf("%v, %v, %v, %v", insertErr, updateErr, deleteErr, testSelectItemNameList(t, db))
+
+			check := `returning not exist, returning not exist, returning not exist, [1]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_WithRecursive(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		// user_id is used as the parent id
+		testInsertItem(t, db, 1, 0)
+		testInsertItem(t, db, 2, 1)
+		testInsertItem(t, db, 3, 2)
+		testInsertItem(t, db, 4, 0)
+
+		testItemTable := TestItem{}
+		testItemPathTable := TestItemPath{}
+
+		baseQuery := db.Query()
+		baseQuery.SetTable(&testItemTable)
+		baseQuery.SetSelect(&testItemTable.Id)
+		baseQuery.SetSelectStringAs("1", "depth")
+		baseQuery.SetWhereIs(&testItemTable.UserId, 0)
+
+		recursiveQuery := db.Query()
+		recursiveQuery.SetTable(&testItemTable)
+		recursiveQuery.SetJoin(&testItemPathTable, &testItemPathTable.Id, &testItemTable.UserId)
+		recursiveQuery.SetSelect(&testItemTable.Id)
+		recursiveQuery.SetSelectFormat("%s + 1", &testItemPathTable.Depth)
+
+		var resultList []TestItemPath
+		query := db.Query()
+		query.SetWithRecursive(&testItemPathTable, baseQuery, recursiveQuery)
+		query.SetTable(&testItemPathTable)
+		query.SetSelectAll(&testItemPathTable)
+		query.SetOrderBy(&testItemPathTable.Id)
+		err := query.Select(&resultList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v", resultList)
+
+			check := `[{1 1} {2 2} {3 3} {4 1}]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_SelectUnion(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 20)
+		testInsertItem(t, db, 3, 30)
+
+		testItemTable := TestItem{}
+		testItemUnionTable := TestItem{}
+
+		unionQuery := db.Query()
+		unionQuery.SetTable(&testItemUnionTable)
+		unionQuery.SetSelect(&testItemUnionTable.Name)
+		unionQuery.SetWhereGte(&testItemUnionTable.UserId, 20)
+
+		var resultList []TestItem
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Name)
+		query.SetWhereLte(&testItemTable.UserId, 20)
+		query.SetUnion(unionQuery)
+		query.SetOrderByDesc(&testItemTable.Name)
+		err := query.Select(&resultList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			var nameList []int
+			for _, result := range resultList {
+				nameList = append(nameList, result.Name)
+			}
+			target := fmt.Sprintf("%v", nameList)
+
+			check := `[3 2 1]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			var countList []map[string]interface{}
+			err := query.SelectCount(&countList)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			target := fmt.Sprintf("%v", countList)
+
+			check := `[map[count(*):3]]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success query limit", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 20)
+		testInsertItem(t, db, 3, 30)
+		testInsertItem(t, db, 4, 40)
+
+		testItemTable := TestItem{}
+		testItemUnionTable := TestItem{}
+
+		unionQuery := db.Query()
+		unionQuery.SetTable(&testItemUnionTable)
+		unionQuery.SetSelect(&testItemUnionTable.Name)
+		unionQuery.SetOrderByDesc(&testItemUnionTable.Name)
+		unionQuery.SetLimit(1)
+
+		var resultList []TestItem
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Name)
+		query.SetWhereLte(&testItemTable.UserId, 20)
+		query.SetUnion(unionQuery)
+		query.SetOrderByDesc(&testItemTable.Name)
+		query.SetLimit(2)
+		err := query.Select(&resultList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			var nameList []int
+			for _, result := range resultList {
+				nameList = append(nameList, result.Name)
+			}
+			target := fmt.Sprintf("%v", nameList)
+
+			check := `[4 2]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			var count int
+			err := query.SelectCount(&count)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			target := fmt.Sprintf("%v", count)
+
+			check := `3`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_UpdateJoin(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 1)
+		testInsertItem(t, db, 2, 2)
+
+		now := time.Now()
+		testUserTable := TestUser{}
+		{
+			query := db.Query()
+			query.SetTable(&testUserTable)
+			query.SetValuesColumn(&testUserTable.CreatedAt, &testUserTable.UpdatedAt, &testUserTable.Name, &testUserTable.Pass)
+			query.SetValues(now, now, "user", "pass")
+			_, err := query.Insert()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetJoin(&testUserTable, &testUserTable.Id, &testItemTable.UserId)
+		query.SetSet(&testItemTable.Name, 3)
+		query.SetWhereIs(&testUserTable.Name, "user")
+		_, err := query.Update()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[2 3]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success join query", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 10)
+		testInsertItem(t, db, 3, 10)
+		testInsertItem(t, db, 4, 20)
+
+		countTable := TestItem{}
+		countQuery := db.Query()
+		countQuery.SetTable(&countTable)
+		countQuery.SetSelect(&countTable.UserId)
+		countQuery.SetSelectStringAs("count(*)", "count")
+		countQuery.SetWhereGte(&countTable.Name, 1)
+		countQuery.SetGroupBy(&countTable.UserId)
+
+		countNameTable := TestItem{}
+		countNameQuery := db.Query()
+		countNameQuery.SetTable(&countNameTable)
+		countNameQuery.SetSelect(&countNameTable.UserId)
+		countNameQuery.SetSelectStringAs("count(*)", "count")
+		countNameQuery.SetWhereGte(&countNameTable.Name, 2)
+		countNameQuery.SetGroupBy(&countNameTable.UserId)
+
+		testItemTable := TestItem{}
+		testItemCountTable := TestItemCount{}
+		testItemCountNameTable := TestItemNameCount{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetJoinQuery(&testItemCountTable, countQuery, &testItemCountTable.UserId, &testItemTable.UserId)
+		query.SetJoinWhereGte(&testItemCountTable, &testItemCountTable.Count, 3)
+		query.SetJoinQuery(&testItemCountNameTable, countNameQuery, &testItemCountNameTable.UserId, &testItemTable.UserId)
+		query.SetJoinWhereGte(&testItemCountNameTable, &testItemCountNameTable.Count, 2)
+		query.SetSet(&testItemTable.Name, 9)
+		query.SetWhereGte(&testItemTable.Name, 2)
+		_, err := query.Update()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[1 4 9 9]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_InsertValuesQuery(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 20)
+
+		testItemTable := TestItem{}
+		testItemCopyTable := TestItem{}
+
+		valuesQuery := db.Query()
+		valuesQuery.SetTable(&testItemCopyTable)
+		valuesQuery.SetSelect(&testItemCopyTable.CreatedAt, &testItemCopyTable.UpdatedAt)
+		valuesQuery.SetSelectFormat("%s + 10", &testItemCopyTable.Name)
+		valuesQuery.SetSelect(&testItemCopyTable.UserId)
+		valuesQuery.SetWhereGte(&testItemCopyTable.UserId, 20)
+
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.CreatedAt, &testItemTable.UpdatedAt, &testItemTable.Name, &testItemTable.UserId)
+		query.SetValuesQuery(valuesQuery)
+		_, err := query.Insert()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[1 2 12]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_UpdateExpr(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 5, 10)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSet(&testItemTable.Name, Expr("%s - %s", Column(&testItemTable.Name), 2))
+		query.SetSet(&testItemTable.UpdatedAt, Expr("CURRENT_TIMESTAMP"))
+		query.SetWhereIs(&testItemTable.UserId, 10)
+		query.SetWhereGte(&testItemTable.Name, 2)
+		_, err := query.Update()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := testSelectItemNameList(t, db)
+
+			check := `[3]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_SelectJoinQuery(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 10)
+		testInsertItem(t, db, 3, 20)
+
+		testItemTable := TestItem{}
+		testItemCountTable := TestItemCount{}
+
+		countQuery := db.Query()
+		countQuery.SetTable(&testItemTable)
+		countQuery.SetSelect(&testItemTable.UserId)
+		countQuery.SetSelectStringAs("count(*)", "count")
+		countQuery.SetGroupBy(&testItemTable.UserId)
+
+		testItemSelectTable := TestItem{}
+
+		var resultList []map[string]interface{}
+		query := db.Query()
+		query.SetTable(&testItemSelectTable)
+		query.SetJoinQuery(&testItemCountTable, countQuery, &testItemCountTable.UserId, &testItemSelectTable.UserId)
+		query.SetSelect(&testItemSelectTable.Name, &testItemCountTable.Count)
+		query.SetOrderBy(&testItemSelectTable.Name)
+		err := query.Select(&resultList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v", resultList)
+
+			check := `[map[count:2 name:1] map[count:2 name:2] map[count:1 name:3]]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_Aggregate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 10)
+		testInsertItem(t, db, 4, 20)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelectAll(&testItemTable)
+		query.SetWhereGte(&testItemTable.Name, 2)
+
+		exists, err := query.Exists()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var sum int
+		err = query.Sum(&testItemTable.Name, &sum)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var max NullInt64
+		err = query.Max(&testItemTable.Name, &max)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var min int
+		err = query.Min(&testItemTable.Name, &min)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var avg float64
+		err = query.Avg(&testItemTable.Name, &avg)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		countDistinct, err := query.CountDistinct(&testItemTable.UserId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var nameList []int
+		query.SetOrderBy(&testItemTable.Name)
+		query.SetLimit(1)
+		err = query.Pluck(&testItemTable.Name, &nameList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v %v %v %v %v %v %v", exists, sum, *max.Get(), min, avg, countDistinct, nameList)
+
+			check := `true 6 4 2 3 2 [2]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success no row", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetWhereIs(&testItemTable.UserId, 10)
+
+		exists, err := query.Exists()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var sum int
+		err = query.Sum(&testItemTable.Name, &sum)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var max NullInt64
+		err = query.Max(&testItemTable.Name, &max)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v %v %v", exists, sum, max.Get())
+
+			check := `false 0 <nil>`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error pluck lock", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetForUpdate()
+
+		var nameList []int
+		err := query.Pluck(&testItemTable.Name, &nameList)
+
+		{
+			target := fmt.Sprint(err)
+
+			check := `lock needs transaction`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success pluck distinct", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 10)
+		testInsertItem(t, db, 4, 20)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetDistinct()
+		query.SetOrderBy(&testItemTable.UserId)
+
+		var userIdList []int
+		err := query.Pluck(&testItemTable.UserId, &userIdList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v", userIdList)
+
+			check := `[10 20]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error distinct", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetDistinct()
+		query.SetSelect(&testItemTable.UserId)
+
+		var sum int
+		err := query.Sum(&testItemTable.UserId, &sum)
+
+		{
+			target := fmt.Sprint(err)
+
+			check := `aggregate with distinct not supported`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error limit", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetOrderBy(&testItemTable.Name)
+		query.SetLimit(10)
+		query.SetOffset(20)
+
+		var avg float64
+		err := query.Avg(&testItemTable.Name, &avg)
+
+		{
+			target := fmt.Sprint(err)
+
+			check := `aggregate with limit not supported`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_ExecQueryDest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 20)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelectAll(&testItemTable)
+		query.SetWhereIs(&testItemTable.Name, 2)
+
+		item := TestItem{}
+		err := query.Select(&item)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var name int
+		err = db.ExecQuery(&name, `SELECT "name" FROM "test_item" WHERE "user_id" = ?`, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var nameStr string
+		err = db.ExecQuery(&nameStr, `SELECT "name" FROM "test_item" ORDER BY "name" DESC`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var createdAt time.Time
+		err = db.ExecQuery(&createdAt, `SELECT "created_at" FROM "test_item" ORDER BY "name"`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var userIdList []int64
+		err = db.ExecQuery(&userIdList, `SELECT "user_id" FROM "test_item" ORDER BY "name"`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var nameStrList []string
+		err = db.ExecQuery(&nameStrList, `SELECT "name" FROM "test_item" ORDER BY "name"`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var itemMap map[string]interface{}
+		err = db.ExecQuery(&itemMap, `SELECT "name", "user_id" FROM "test_item" ORDER BY "name"`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v %v %v %v %v %v %v %v", item.Name, item.UserId, name, nameStr, createdAt.IsZero(), userIdList, nameStrList, itemMap)
+
+			check := `2 20 1 2 false [10 20] [1 2] map[name:1 user_id:10]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error not found", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelectAll(&testItemTable)
+
+		item := TestItem{}
+		err := query.Select(&item)
+
+		{
+			target := fmt.Sprintf("%v %v", errors.Is(err, ErrNotFound), errors.Is(err, sql.ErrNoRows))
+
+			check := `true true`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		var itemList []TestItem
+		err = query.Select(&itemList)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("error column length", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		var name int
+		err := db.ExecQuery(&name, `SELECT "name", "user_id" FROM "test_item"`)
+
+		{
+			target := fmt.Sprint(err)
+
+			check := `column length is not 1`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_Rows(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 20)
+		testInsertItem(t, db, 3, 20)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelectAll(&testItemTable)
+		query.SetOrderBy(&testItemTable.Name)
+
+		rows, err := query.Rows()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		var resultList []string
+		for rows.Next() {
+			item := TestItem{}
+			err = rows.Scan(&item)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			resultList = append(resultList, fmt.Sprintf("%v:%v", item.Name, item.UserId))
+		}
+
+		err = rows.Err()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := strings.Join(resultList, " ")
+
+			check := `1:10 2:20 3:20`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success each", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 20)
+		testInsertItem(t, db, 3, 20)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelectAll(&testItemTable)
+		query.SetWhereIs(&testItemTable.UserId, 20)
+		query.SetOrderBy(&testItemTable.Name)
+
+		var resultList []string
+		item := TestItem{}
+		err := query.Each(&item, func() error {
+			resultList = append(resultList, fmt.Sprintf("%v:%v", item.Name, item.UserId))
+			return nil
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := strings.Join(resultList, " ")
+
+			check := `2:20 3:20`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error each stop", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testInsertItem(t, db, 1, 10)
+		testInsertItem(t, db, 2, 20)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Name)
+		query.SetOrderBy(&testItemTable.Name)
+
+		count := 0
+		var name int
+		err := query.Each(&name, func() error {
+			count++
+			return errors.New("stop")
+		})
+
+		{
+			target := fmt.Sprintf("%v %v %v", err, count, name)
+
+			check := `stop 1 1`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error lock", func(t *testing.T) {
+		db := testOpenSqlite(t)
+
+		testItemTable := TestItem{}
+		query := db.Query()
+		query.SetTable(&testItemTable)
+		query.SetSelectAll(&testItemTable)
+		query.SetForUpdate()
+
+		_, err := query.Rows()
+
+		{
+			target := fmt.Sprint(err)
+
+			check := `lock needs transaction`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,508 @@
+package gol
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// INSERT / UPDATE / DELETE ... RETURNING
+	DialectFeatureReturning = iota
+	// INSERT ... ON CONFLICT (...) DO NOTHING / DO UPDATE SET ... = EXCLUDED. ...
+	DialectFeatureOnConflict
+	// INSERT ... ON DUPLICATE KEY UPDATE ... = VALUES(...)
+	DialectFeatureOnDuplicateKey
+	// SELECT ... FOR UPDATE / FOR SHARE [OF ...] [NOWAIT | SKIP LOCKED]
+	DialectFeatureLock
+	// SELECT ... FOR NO KEY UPDATE
+	DialectFeatureLockNoKeyUpdate
+	// SELECT DISTINCT ON (...)
+	DialectFeatureDistinctOn
+	// UPDATE ... SET ... FROM ...
+	DialectFeatureUpdateFrom
+	// DELETE FROM ... USING ...
+	DialectFeatureDeleteUsing
+	// UPDATE ... JOIN ... SET ...
+	DialectFeatureUpdateJoin
+	// DELETE ... FROM ... JOIN ...
+	DialectFeatureDeleteJoin
+	// FULL OUTER JOIN
+	DialectFeatureJoinFull
+	// JOIN LATERAL (...)
+	DialectFeatureJoinLateral
+	// SELECT ... UNION (SELECT ... ORDER BY ... LIMIT ...)
+	DialectFeatureUnionParentheses
+)
+
+// Dialect is the database specific part of the query builder and of Open.
+// Register your own implementation with RegisterDialect to use a database that gol does not know.
+type Dialect interface {
+	// Driver name passed to sql.Open.
+	DriverName() string
+	// Quote a table or column name.
+	QuoteIdentifier(name string) string
+	// Bind parameter for the index-th value. index starts at 1.
+	Placeholder(index int) string
+	// LIMIT / OFFSET clause. A value less than 1 means not set.
+	LimitOffset(limit int, offset int) string
+	// Data source name passed to sql.Open.
+	DataSourceName(host string, port string, user string, pass string, database string, option *OptionType) (string, error)
+	// Whether the database supports DialectFeature*.
+	Support(feature int) bool
+}
+
+var dialectMap = make(map[string]Dialect)
+var dialectMutex sync.RWMutex
+
+func init() {
+	RegisterDialect(DatabaseTypePostgresql, &DialectPostgresql{})
+	RegisterDialect(DatabaseTypeMysql, &DialectMysql{})
+	RegisterDialect(DatabaseTypeSqlite, &DialectSqlite{})
+}
+
+func RegisterDialect(databaseType string, dialect Dialect) {
+	dialectMutex.Lock()
+	defer dialectMutex.Unlock()
+
+	dialectMap[databaseType] = dialect
+}
+
+func GetDialect(databaseType string) (Dialect, error) {
+	dialectMutex.RLock()
+	defer dialectMutex.RUnlock()
+
+	dialect, ok := dialectMap[databaseType]
+	if !ok {
+		return nil, errors.New("unknown databaseType")
+	}
+
+	return dialect, nil
+}
+
+func checkSslMode(sslMode string) error {
+	switch sslMode {
+	case DatabaseSslModeDisable, DatabaseSslModeRequire, DatabaseSslModeVerifyCa, DatabaseSslModeVerifyFull:
+		return nil
+	}
+
+	return errors.New("unknown sslMode")
+}
+
+// makeTLSConfig makes the tls.Config that works like sslmode of postgresql.
+func makeTLSConfig(host string, sslMode string, option *OptionType) (*tls.Config, error) {
+	config := &tls.Config{}
+
+	if option.SslRootCert != "" {
+		pem, err := os.ReadFile(option.SslRootCert)
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("sslRootCert has no certificate")
+		}
+	}
+
+	if option.SslCert != "" || option.SslKey != "" {
+		certificate, err := tls.LoadX509KeyPair(option.SslCert, option.SslKey)
+		if err != nil {
+			return nil, err
+		}
+
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	switch sslMode {
+	case DatabaseSslModeRequire:
+		config.InsecureSkipVerify = true
+	case DatabaseSslModeVerifyCa:
+		// check the certificate chain without the host name
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = func(rawCertList [][]byte, _ [][]*x509.Certificate) error {
+			var certificateList []*x509.Certificate
+			for _, rawCert := range rawCertList {
+				certificate, err := x509.ParseCertificate(rawCert)
+				if err != nil {
+					return err
+				}
+				certificateList = append(certificateList, certificate)
+			}
+
+			if len(certificateList) < 1 {
+				return errors.New("server certificate not exist")
+			}
+
+			intermediates := x509.NewCertPool()
+			for _, certificate := range certificateList[1:] {
+				intermediates.AddCert(certificate)
+			}
+
+			_, err := certificateList[0].Verify(x509.VerifyOptions{
+				Roots:         config.RootCAs,
+				Intermediates: intermediates,
+			})
+
+			return err
+		}
+	case DatabaseSslModeVerifyFull:
+		config.ServerName = host
+	}
+
+	return config, nil
+}
+
+func makeLimitOffset(limit int, offset int) string {
+	var strList []string
+
+	if limit > 0 {
+		strList = append(strList, fmt.Sprintf("LIMIT %v", limit))
+	}
+
+	if offset > 0 {
+		strList = append(strList, fmt.Sprintf("OFFSET %v", offset))
+	}
+
+	return strings.Join(strList, " ")
+}
+
+type DialectPostgresql struct{}
+
+func (rec *DialectPostgresql) DriverName() string {
+	return DatabaseTypePostgresql
+}
+
+func (rec *DialectPostgresql) QuoteIdentifier(name string) string {
+	return fmt.Sprintf("\"%v\"", strings.Replace(name, "\"", "\"\"", -1))
+}
+
+func (rec *DialectPostgresql) Placeholder(index int) string {
+	return fmt.Sprintf("$%v", index)
+}
+
+func (rec *DialectPostgresql) LimitOffset(limit int, offset int) string {
+	return makeLimitOffset(limit, offset)
+}
+
+func (rec *DialectPostgresql) DataSourceName(host string, port string, user string, pass string, database string, option *OptionType) (string, error) {
+	if option.TlsConfig != nil {
+		return "", errors.New("tlsConfig is not supported by postgresql. Use sslRootCert, sslCert and sslKey")
+	}
+
+	sslMode := DatabaseSslModeDisable
+	if option.SslMode != "" {
+		err := checkSslMode(option.SslMode)
+		if err != nil {
+			return "", err
+		}
+		sslMode = option.SslMode
+	}
+
+	type paramType struct {
+		Key   string
+		Value string
+	}
+
+	paramList := []*paramType{
+		{Key: "host", Value: host},
+		{Key: "port", Value: port},
+		{Key: "user", Value: user},
+		{Key: "password", Value: pass},
+		{Key: "dbname", Value: database},
+		{Key: "sslmode", Value: sslMode},
+	}
+
+	if option.SslRootCert != "" {
+		paramList = append(paramList, &paramType{Key: "sslrootcert", Value: option.SslRootCert})
+	}
+	if option.SslCert != "" {
+		paramList = append(paramList, &paramType{Key: "sslcert", Value: option.SslCert})
+	}
+	if option.SslKey != "" {
+		paramList = append(paramList, &paramType{Key: "sslkey", Value: option.SslKey})
+	}
+
+	keyMap := make(map[string]bool)
+	for _, param := range paramList {
+		keyMap[param.Key] = true
+	}
+
+	var keyList []string
+	for key := range option.ParamMap {
+		// pq uses the last value of the same key
+		if keyMap[key] {
+			return "", fmt.Errorf("option %s is duplicated", key)
+		}
+		keyList = append(keyList, key)
+	}
+	sort.Strings(keyList)
+	for _, key := range keyList {
+		paramList = append(paramList, &paramType{Key: key, Value: option.ParamMap[key]})
+	}
+
+	var strList []string
+	for _, param := range paramList {
+		strList = append(strList, fmt.Sprintf("%s=%s", param.Key, quotePostgresqlValue(param.Value)))
+	}
+
+	source := strings.Join(strList, " ")
+
+	return source, nil
+}
+
+// quotePostgresqlValue quotes a value of "key=value" connection string when it is empty or has a space, a quote or a backslash.
+func quotePostgresqlValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r'\\") {
+		return value
+	}
+
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	value = strings.Replace(value, "'", "\\'", -1)
+
+	return fmt.Sprintf("'%s'", value)
+}
+
+func (rec *DialectPostgresql) Support(feature int) bool {
+	switch feature {
+	case DialectFeatureReturning, DialectFeatureOnConflict, DialectFeatureLock, DialectFeatureLockNoKeyUpdate, DialectFeatureDistinctOn, DialectFeatureUpdateFrom, DialectFeatureDeleteUsing, DialectFeatureJoinFull, DialectFeatureJoinLateral, DialectFeatureUnionParentheses:
+		return true
+	}
+
+	return false
+}
+
+type DialectMysql struct{}
+
+func (rec *DialectMysql) DriverName() string {
+	return DatabaseTypeMysql
+}
+
+func (rec *DialectMysql) QuoteIdentifier(name string) string {
+	return name
+}
+
+func (rec *DialectMysql) Placeholder(index int) string {
+	return "?"
+}
+
+func (rec *DialectMysql) LimitOffset(limit int, offset int) string {
+	// mysql can not use OFFSET without LIMIT
+	if limit < 1 && offset > 0 {
+		return fmt.Sprintf("LIMIT 18446744073709551615 OFFSET %v", offset)
+	}
+
+	return makeLimitOffset(limit, offset)
+}
+
+func (rec *DialectMysql) DataSourceName(host string, port string, user string, pass string, database string, option *OptionType) (string, error) {
+	// [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
+	config := mysql.NewConfig()
+	config.User = user
+	config.Passwd = pass
+	config.Net = "tcp"
+	config.Addr = net.JoinHostPort(host, port)
+	config.DBName = database
+
+	// parseTime, loc, charset, collation, timeout ... are passed as they are
+	config.Params = make(map[string]string)
+	for key, value := range option.ParamMap {
+		config.Params[key] = value
+	}
+
+	_, ok := option.ParamMap["tls"]
+	if ok && (option.SslMode != "" || option.TlsConfig != nil) {
+		return "", errors.New("option tls is duplicated. Use sslMode or tlsConfig")
+	}
+
+	// the tls.Config is registered with the driver by DB.InitOption, so that this has no side effects
+	tlsName := option.tlsConfigName
+	if tlsName == "" {
+		name, tlsConfig, err := makeMysqlTLSConfig(host, option)
+		if err != nil {
+			return "", err
+		}
+		if tlsConfig != nil {
+			return "", errors.New("tls config is not registered. Use Open")
+		}
+
+		tlsName = name
+	}
+	if tlsName != "" {
+		config.Params["tls"] = tlsName
+	}
+
+	source := config.FormatDSN()
+
+	_, err := mysql.ParseDSN(source)
+	if err != nil {
+		return "", err
+	}
+
+	return source, nil
+}
+
+// makeMysqlTLSConfig returns the value of "tls" parameter known by the driver,
+// or the tls.Config made from option when the driver does not know it.
+func makeMysqlTLSConfig(host string, option *OptionType) (string, *tls.Config, error) {
+	sslMode := option.SslMode
+	if sslMode == "" {
+		sslMode = DatabaseSslModeDisable
+	}
+
+	err := checkSslMode(sslMode)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if option.TlsConfig != nil {
+		return "", option.TlsConfig.Clone(), nil
+	}
+
+	hasFile := option.SslRootCert != "" || option.SslCert != "" || option.SslKey != ""
+
+	switch sslMode {
+	case DatabaseSslModeDisable:
+		return "", nil, nil
+	case DatabaseSslModeRequire:
+		if !hasFile {
+			return "skip-verify", nil, nil
+		}
+	case DatabaseSslModeVerifyFull:
+		if !hasFile {
+			return "true", nil, nil
+		}
+	}
+
+	config, err := makeTLSConfig(host, sslMode, option)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "", config, nil
+}
+
+const mysqlTLSConfigPrefix = "gol_tls_"
+
+// mysqlTLSConfigCount numbers the registered tls.Config.
+var mysqlTLSConfigCount int64
+
+// registerMysqlTLSConfig registers the tls.Config made from option with the mysql driver and returns the name.
+// It returns "" if the dialect is not mysql or the driver knows the "tls" parameter. DB.Close deregisters it.
+func registerMysqlTLSConfig(dialect Dialect, host string, option *OptionType) (string, error) {
+	_, ok := dialect.(*DialectMysql)
+	if !ok {
+		return "", nil
+	}
+
+	_, config, err := makeMysqlTLSConfig(host, option)
+	if err != nil {
+		return "", err
+	}
+	if config == nil {
+		return "", nil
+	}
+
+	name := fmt.Sprintf("%s%v", mysqlTLSConfigPrefix, atomic.AddInt64(&mysqlTLSConfigCount, 1))
+
+	err = mysql.RegisterTLSConfig(name, config)
+	if err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+func deregisterMysqlTLSConfig(name string) {
+	if name == "" {
+		return
+	}
+
+	mysql.DeregisterTLSConfig(name)
+}
+
+func (rec *DialectMysql) Support(feature int) bool {
+	switch feature {
+	case DialectFeatureOnDuplicateKey, DialectFeatureLock, DialectFeatureUpdateJoin, DialectFeatureDeleteJoin, DialectFeatureJoinLateral, DialectFeatureUnionParentheses:
+		return true
+	}
+
+	return false
+}
+
+type DialectSqlite struct{}
+
+func (rec *DialectSqlite) DriverName() string {
+	return DatabaseTypeSqlite
+}
+
+func (rec *DialectSqlite) QuoteIdentifier(name string) string {
+	return fmt.Sprintf("\"%v\"", strings.Replace(name, "\"", "\"\"", -1))
+}
+
+func (rec *DialectSqlite) Placeholder(index int) string {
+	return "?"
+}
+
+func (rec *DialectSqlite) LimitOffset(limit int, offset int) string {
+	// sqlite can not use OFFSET without LIMIT
+	if limit < 1 && offset > 0 {
+		return fmt.Sprintf("LIMIT -1 OFFSET %v", offset)
+	}
+
+	return makeLimitOffset(limit, offset)
+}
+
+// sqliteMemoryCount numbers the in-memory databases, so that each Open has its own.
+var sqliteMemoryCount int64
+
+// DataSourceName makes "file:database?param=value".
+// host, port, user and pass are not used.
+// An empty database or ":memory:" is an in-memory database shared by the connections of the pool. Each Open has its own database.
+// ParamMap is passed as uri parameters. "mode" and "cache" are sqlite uri parameters, and keys beginning with "_" are driver pragmas such as "_foreign_keys".
+func (rec *DialectSqlite) DataSourceName(host string, port string, user string, pass string, database string, option *OptionType) (string, error) {
+	paramMap := url.Values{}
+
+	path := database
+	if path == "" || path == ":memory:" {
+		// a name for each Open. The connections of the pool share it, and the other Open does not
+		path = fmt.Sprintf("gol_memory_%v", atomic.AddInt64(&sqliteMemoryCount, 1))
+		paramMap.Set("mode", "memory")
+		paramMap.Set("cache", "shared")
+	}
+
+	for key, value := range option.ParamMap {
+		paramMap.Set(key, value)
+	}
+
+	if paramMap.Get("mode") == "memory" && paramMap.Get("cache") == "" {
+		paramMap.Set("cache", "shared")
+	}
+
+	path = strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23").Replace(path)
+
+	source := fmt.Sprintf("file:%s", path)
+	if len(paramMap) > 0 {
+		source = fmt.Sprintf("%s?%s", source, paramMap.Encode())
+	}
+
+	return source, nil
+}
+
+func (rec *DialectSqlite) Support(feature int) bool {
+	switch feature {
+	case DialectFeatureReturning, DialectFeatureOnConflict, DialectFeatureUpdateFrom, DialectFeatureJoinFull:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,325 @@
+package gol
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGetDialect(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		dialect, err := GetDialect(DatabaseTypeMysql)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := dialect.DriverName()
+
+			check := DatabaseTypeMysql
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error unknown databaseType", func(t *testing.T) {
+		_, err := GetDialect("unknown")
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `unknown databaseType`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_Dialect(t *testing.T) {
+	t.Run("success mysql", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetWhereIs(&testItemTable.UserId, 1)
+		query.SetOffset(10)
+		str, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT test_item.id FROM test_item WHERE test_item.user_id = ? LIMIT 18446744073709551615 OFFSET 10`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success sqlite", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeSqlite)
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetWhereIs(&testItemTable.UserId, 1)
+		query.SetOffset(10)
+		str, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "test_item"."id" FROM "test_item" WHERE "test_item"."user_id" = ? LIMIT -1 OFFSET 10`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error unknown databaseType", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.Init(nil, nil, "unknown")
+		query.SetTable(&testItemTable)
+		query.SetSelectAll(&testItemTable)
+		_, _, err := query.GetSelectQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `unknown databaseType`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestDialectSqlite_DataSourceName(t *testing.T) {
+	t.Run("success file", func(t *testing.T) {
+		dialect := &DialectSqlite{}
+		target, err := dialect.DataSourceName("", "", "", "", "/tmp/data?.db", &OptionType{SslMode: DatabaseSslModeDisable, ParamMap: map[string]string{"_foreign_keys": "1"}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		check := `file:/tmp/data%3f.db?_foreign_keys=1`
+
+		if target != check {
+			t.Error("target:", target)
+			t.Error("check :", check)
+			return
+		}
+	})
+
+	t.Run("success memory", func(t *testing.T) {
+		dialect := &DialectSqlite{}
+		source, err := dialect.DataSourceName("", "", "", "", ":memory:", &OptionType{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		otherSource, err := dialect.DataSourceName("", "", "", "", "", &OptionType{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		target := fmt.Sprintf("%v %v %v", strings.HasPrefix(source, "file:gol_memory_"), strings.HasSuffix(source, "?cache=shared&mode=memory"), source != otherSource)
+
+		check := `true true true`
+
+		if target != check {
+			t.Error("target:", target)
+			t.Error("check :", check)
+			return
+		}
+	})
+}
+
+func TestDialectPostgresql_DataSourceName(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		dialect := &DialectPostgresql{}
+		target, err := dialect.DataSourceName("localhost", "5432", "user", `pa ss'\word`, "database", &OptionType{ParamMap: map[string]string{"connect_timeout": "10", "application_name": "gol"}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		check := `host=localhost port=5432 user=user password='pa ss\'\\word' dbname=database sslmode=disable application_name=gol connect_timeout=10`
+
+		if target != check {
+			t.Error("target:", target)
+			t.Error("check :", check)
+			return
+		}
+	})
+
+	t.Run("error duplicated", func(t *testing.T) {
+		dialect := &DialectPostgresql{}
+		_, err := dialect.DataSourceName("localhost", "5432", "user", "pass", "database", &OptionType{SslMode: DatabaseSslModeVerifyFull, ParamMap: map[string]string{"sslmode": "disable"}})
+
+		target := fmt.Sprintf("%v", err)
+
+		check := `option sslmode is duplicated`
+
+		if target != check {
+			t.Error("target:", target)
+			t.Error("check :", check)
+			return
+		}
+	})
+}
+
+func TestDialectMysql_DataSourceName(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		dialect := &DialectMysql{}
+		target, err := dialect.DataSourceName("localhost", "3306", "user", "p@ss/word", "database", &OptionType{ParamMap: map[string]string{"parseTime": "true", "loc": "Asia/Tokyo", "charset": "utf8mb4"}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		check := `user:p@ss/word@tcp(localhost:3306)/database?charset=utf8mb4&loc=Asia%2FTokyo&parseTime=true`
+
+		if target != check {
+			t.Error("target:", target)
+			t.Error("check :", check)
+			return
+		}
+	})
+
+	t.Run("error tls duplicated", func(t *testing.T) {
+		dialect := &DialectMysql{}
+		_, err := dialect.DataSourceName("localhost", "3306", "user", "pass", "database", &OptionType{SslMode: DatabaseSslModeDisable, ParamMap: map[string]string{"tls": "skip-verify"}})
+
+		target := fmt.Sprintf("%v", err)
+
+		check := `option tls is duplicated. Use sslMode or tlsConfig`
+
+		if target != check {
+			t.Error("target:", target)
+			t.Error("check :", check)
+			return
+		}
+	})
+}
+
+func TestDialect_DataSourceNameSsl(t *testing.T) {
+	t.Run("success postgresql certificate", func(t *testing.T) {
+		dialect := &DialectPostgresql{}
+		target, err := dialect.DataSourceName("localhost", "5432", "user", "pass", "database", &OptionType{
+			SslMode:     DatabaseSslModeVerifyFull,
+			SslRootCert: "/etc/ssl/ca.pem",
+			SslCert:     "/etc/ssl/client.pem",
+			SslKey:      "/etc/ssl/client.key",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		check := `host=localhost port=5432 user=user password=pass dbname=database sslmode=verify-full sslrootcert=/etc/ssl/ca.pem sslcert=/etc/ssl/client.pem sslkey=/etc/ssl/client.key`
+
+		if target != check {
+			t.Error("target:", target)
+			t.Error("check :", check)
+			return
+		}
+	})
+
+	t.Run("success mysql require", func(t *testing.T) {
+		dialect := &DialectMysql{}
+		target, err := dialect.DataSourceName("localhost", "3306", "user", "pass", "database", &OptionType{SslMode: DatabaseSslModeRequire})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		check := `user:pass@tcp(localhost:3306)/database?tls=skip-verify`
+
+		if target != check {
+			t.Error("target:", target)
+			t.Error("check :", check)
+			return
+		}
+	})
+
+	t.Run("error postgresql unknown sslMode", func(t *testing.T) {
+		dialect := &DialectPostgresql{}
+		_, err := dialect.DataSourceName("localhost", "5432", "user", "pass", "database", &OptionType{SslMode: "prefer"})
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `unknown sslMode`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error mysql certificate not exist", func(t *testing.T) {
+		dialect := &DialectMysql{}
+		_, err := dialect.DataSourceName("localhost", "3306", "user", "pass", "database", &OptionType{SslMode: DatabaseSslModeVerifyCa, SslRootCert: "/not/exist/ca.pem"})
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `open /not/exist/ca.pem: no such file or directory`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error mysql tls config not registered", func(t *testing.T) {
+		dialect := &DialectMysql{}
+		_, err := dialect.DataSourceName("localhost", "3306", "user", "pass", "database", &OptionType{TlsConfig: &tls.Config{ServerName: "localhost"}})
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `tls config is not registered. Use Open`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
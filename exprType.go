@@ -0,0 +1,27 @@
+package gol
+
+// ExprType is a SQL expression used as a value of SetSet, SetValues and SetWhere*.
+// Each %s of Format is replaced with the value of ArgList in order. The other % is kept as it is.
+// A ColumnType is replaced with the column name and the other values are bind parameters.
+type ExprType struct {
+	Format  string
+	ArgList []interface{}
+}
+
+// ColumnType is a column used as a value.
+type ColumnType struct {
+	ColumnPtr interface{}
+}
+
+func Expr(format string, argList ...interface{}) *ExprType {
+	return &ExprType{
+		Format:  format,
+		ArgList: argList,
+	}
+}
+
+func Column(columnPtr interface{}) *ColumnType {
+	return &ColumnType{
+		ColumnPtr: columnPtr,
+	}
+}
@@ -0,0 +1,100 @@
+package gol
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type OptionType struct {
+	// DatabaseSslMode*
+	SslMode string
+	// Path of the CA certificate to verify the server.
+	SslRootCert string
+	// Path of the client certificate.
+	SslCert string
+	// Path of the client key.
+	SslKey string
+	// TLS config used instead of SslMode and the certificate paths. mysql only.
+	TlsConfig *tls.Config
+	// sql.DB.SetMaxOpenConns. 0 is not set.
+	MaxOpenConns int
+	// sql.DB.SetMaxIdleConns. 0 is not set, a negative value keeps no idle connection.
+	MaxIdleConns int
+	// sql.DB.SetConnMaxLifetime. 0 is not set.
+	ConnMaxLifetime time.Duration
+	// sql.DB.SetConnMaxIdleTime. 0 is not set.
+	ConnMaxIdleTime time.Duration
+	// Ping the database in Open with this timeout. 0 is not ping.
+	PingTimeout time.Duration
+	// Keys that gol does not use. Passed to the Dialect.
+	ParamMap map[string]string
+	// Name of the tls.Config registered with the mysql driver by DB.InitOption.
+	tlsConfigName string
+}
+
+// optionKeyMap is the keys of the data source name that are the fields of OptionType.
+// They are set to the fields, so that they are checked and do not override the fields.
+var optionKeyMap = map[string]string{
+	"sslmode":     "sslMode",
+	"sslrootcert": "sslRootCert",
+	"sslcert":     "sslCert",
+	"sslkey":      "sslKey",
+}
+
+func getOptionKey(key string) string {
+	optionKey, ok := optionKeyMap[key]
+	if ok {
+		return optionKey
+	}
+
+	return key
+}
+
+func makeOption(optionMap map[string]string) (*OptionType, error) {
+	option := &OptionType{
+		ParamMap: make(map[string]string),
+	}
+
+	for key, value := range optionMap {
+		var err error
+
+		optionKey := getOptionKey(key)
+		if optionKey != key {
+			optionValue, ok := optionMap[optionKey]
+			if ok && optionValue != value {
+				return nil, fmt.Errorf("option %s is different from %s", key, optionKey)
+			}
+		}
+
+		switch optionKey {
+		case "sslMode":
+			option.SslMode = value
+		case "sslRootCert":
+			option.SslRootCert = value
+		case "sslCert":
+			option.SslCert = value
+		case "sslKey":
+			option.SslKey = value
+		case "maxOpenConns":
+			option.MaxOpenConns, err = strconv.Atoi(value)
+		case "maxIdleConns":
+			option.MaxIdleConns, err = strconv.Atoi(value)
+		case "connMaxLifetime":
+			option.ConnMaxLifetime, err = time.ParseDuration(value)
+		case "connMaxIdleTime":
+			option.ConnMaxIdleTime, err = time.ParseDuration(value)
+		case "pingTimeout":
+			option.PingTimeout, err = time.ParseDuration(value)
+		default:
+			option.ParamMap[key] = value
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("option %s: %w", key, err)
+		}
+	}
+
+	return option, nil
+}
@@ -0,0 +1,89 @@
+package gol
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMakeOption(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		option, err := makeOption(map[string]string{
+			"sslMode":         DatabaseSslModeRequire,
+			"maxOpenConns":    "20",
+			"maxIdleConns":    "-1",
+			"connMaxLifetime": "5m",
+			"connMaxIdleTime": "30s",
+			"pingTimeout":     "3s",
+			"mode":            "memory",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v %v %v %v %v %v %v", option.SslMode, option.MaxOpenConns, option.MaxIdleConns, option.ConnMaxLifetime, option.ConnMaxIdleTime, option.PingTimeout, option.ParamMap)
+
+			check := `require 20 -1 5m0s 30s 3s map[mode:memory]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error invalid value", func(t *testing.T) {
+		_, err := makeOption(map[string]string{"connMaxLifetime": "5"})
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `option connMaxLifetime: time: missing unit in duration "5"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success dsn key", func(t *testing.T) {
+		option, err := makeOption(map[string]string{
+			"sslmode":     DatabaseSslModeVerifyCa,
+			"sslrootcert": "/etc/ssl/ca.pem",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v %v %v", option.SslMode, option.SslRootCert, option.ParamMap)
+
+			check := `verify-ca /etc/ssl/ca.pem map[]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error dsn key different", func(t *testing.T) {
+		_, err := makeOption(map[string]string{"sslmode": DatabaseSslModeDisable, "sslMode": DatabaseSslModeRequire})
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `option sslmode is different from sslMode`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
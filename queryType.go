@@ -1,11 +1,13 @@
 package gol
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 const (
@@ -44,8 +46,37 @@ const (
 
 	Asc = iota
 	Desc
+
+	// added after Asc and Desc so that the exported values do not change
+	joinModeFull = iota
+	joinModeCross
+
+	queryModeExists = iota
+	queryModeExistsNot
+	queryModeQuery
+
+	unionModeUnion = iota
+	unionModeUnionAll
+	unionModeIntersect
+	unionModeExcept
+
+	lockModeUpdate = iota
+	lockModeNoKeyUpdate
+	lockModeShare
+
+	lockWaitNone = iota
+	lockWaitNowait
+	lockWaitSkipLocked
+
+	conflictModeNone = iota
+	conflictModeNothing
+	conflictModeUpdate
 )
 
+// ErrNotFound is returned when no row is scanned into a dest that is not a slice.
+// errors.Is(err, sql.ErrNoRows) is also true.
+var ErrNotFound = fmt.Errorf("not found: %w", sql.ErrNoRows)
+
 type tableType struct {
 	Str      string
 	TablePtr interface{}
@@ -58,6 +89,8 @@ type joinType struct {
 	TableAs        string
 	ColumnPtr      interface{}
 	WhereColumnPtr interface{}
+	Query          *QueryType
+	Lateral        bool
 }
 
 type joinWhereType struct {
@@ -69,11 +102,19 @@ type joinWhereType struct {
 	ValueList []interface{}
 }
 
+type withType struct {
+	TablePtr       interface{}
+	Query          *QueryType
+	RecursiveQuery *QueryType
+}
+
 type selectType struct {
 	Mode      int
 	Str       string
 	ColumnPtr interface{}
 	ColumnAs  interface{}
+	Query     *QueryType
+	Window    *WindowType
 }
 
 type setType struct {
@@ -118,10 +159,44 @@ type orderByType struct {
 	ColumnPtr interface{}
 }
 
+type distinctType struct {
+	ColumnPtrList []interface{}
+}
+
+type windowDefType struct {
+	Name   string
+	Window *WindowType
+}
+
+type unionType struct {
+	Mode  int
+	Query *QueryType
+}
+
+type lockType struct {
+	Mode         int
+	TablePtrList []interface{}
+	Wait         int
+}
+
+type conflictType struct {
+	Mode                int
+	ColumnPtrList       []interface{}
+	UpdateColumnPtrList []interface{}
+}
+
+type returningType struct {
+	ColumnPtr interface{}
+}
+
 type buildType struct {
+	With           string
 	Table          string
+	TableAs        string
 	TableForSelect string
 	Join           string
+	JoinTable      string
+	JoinOn         string
 	Select         string
 	Set            string
 	ValuesColumn   string
@@ -130,10 +205,19 @@ type buildType struct {
 	WhereForSelect string
 	GroupBy        string
 	Having         string
+	Window         string
+	Union          string
 	Order          string
 	Limit          string
-	Offset         string
+	Lock           string
+	Conflict       string
+	Returning      string
 	ValueList      []interface{}
+	// values of JoinTable and JoinOn, to reorder them for UPDATE ... FROM and DELETE ... USING
+	JoinTableValueList []interface{}
+	JoinOnValueList    []interface{}
+	// UPDATE / DELETE without join has no table alias, so Column is built without the table
+	ColumnUnqualified bool
 }
 
 type metaType struct {
@@ -154,23 +238,32 @@ type QueryType struct {
 	modeLog           bool
 	modeResultKey     int
 	modeResetAuto     bool
-	getTableName      func(string, string) (string, string)
-	getColumnName     func(string, string, string) (string, string, string)
-	getPlaceholder    func() string
+	dialect           Dialect
+	parent            *QueryType
+	WithList          []*withType
 	Table             *tableType
 	JoinList          []*joinType
 	JoinWhereList     []*joinWhereType
+	Distinct          *distinctType
 	SelectList        []*selectType
 	SetList           []*setType
 	ValuesColumnList  []*valuesColumnType
 	ValuesColumnCount int
 	ValuesList        [][]*valuesType
+	ValuesQuery       *QueryType
 	WhereList         []*whereType
 	Limit             int
 	Offset            int
 	GroupByList       []*groupByType
 	HavingList        []*havingType
+	WindowList        []*windowDefType
 	OrderByList       []*orderByType
+	UnionList         []*unionType
+	Lock              *lockType
+	Conflict          *conflictType
+	ConflictWhereList []*whereType
+	ReturningList     []*returningType
+	LastInsertIdPtr   interface{}
 	Data              *buildType
 	MetaMap           map[string]*metaType
 }
@@ -181,23 +274,30 @@ func (rec *QueryType) Init(db *sql.DB, tx *sql.Tx, databaseType string) {
 
 	rec.modeResetAuto = true
 
-	switch databaseType {
-	case DatabaseTypeMysql:
-		rec.modeDatabaseType = databaseType
-		rec.getTableName = rec.getTableNameMysql
-		rec.getColumnName = rec.getColumnNameMysql
-		rec.getPlaceholder = rec.getPlaceHolderForMysql
-	case DatabaseTypePostgresql:
-		rec.modeDatabaseType = databaseType
-		rec.getTableName = rec.getTableNamePostgresql
-		rec.getColumnName = rec.getColumnNamePostgresql
-		rec.getPlaceholder = rec.getPlaceHolderForPostgresql
-	default:
+	rec.modeDatabaseType = databaseType
+	rec.dialect = nil
+	dialect, err := GetDialect(databaseType)
+	if err == nil {
+		rec.dialect = dialect
 	}
 
 	rec.Reset()
 }
 
+// newQuery makes an empty QueryType on the same database and modes.
+func (rec *QueryType) newQuery() *QueryType {
+	queryData := &QueryType{}
+
+	queryData.Init(rec.DB, rec.TX, rec.modeDatabaseType)
+	if rec.dialect != nil {
+		queryData.dialect = rec.dialect
+	}
+	queryData.modeLog = rec.modeLog
+	queryData.modeResultKey = rec.modeResultKey
+
+	return queryData
+}
+
 func (rec *QueryType) Reset() {
 	rec.ValuesColumnCount = 0
 }
@@ -222,48 +322,66 @@ func (rec *QueryType) SetModeResultKeySnakeCase() {
 	rec.modeResultKey = resultKeyModeSnakeCase
 }
 
-func (rec *QueryType) getTableNameMysql(tableBase string, tableAsBase string) (string, string) {
-	table := fmt.Sprintf("%v", tableBase)
-	tableAs := table
-	if tableAsBase != "" {
-		tableAs = fmt.Sprintf("%v", tableBase)
+func (rec *QueryType) getDialect() (Dialect, error) {
+	if rec.dialect != nil {
+		return rec.dialect, nil
 	}
-	return table, tableAs
-}
 
-func (rec *QueryType) getColumnNameMysql(table string, tableAs string, columnBase string) (string, string, string) {
-	tableColumn := fmt.Sprintf("%v.%v", table, columnBase)
-	tableAsColumn := fmt.Sprintf("%v.%v", tableAs, columnBase)
-	column := fmt.Sprintf("%v", columnBase)
-	return tableColumn, tableAsColumn, column
-}
+	// QueryType{} without Init is built as postgresql
+	databaseType := rec.modeDatabaseType
+	if databaseType == "" {
+		databaseType = DatabaseTypePostgresql
+	}
+
+	dialect, err := GetDialect(databaseType)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.dialect = dialect
 
-func (rec *QueryType) getPlaceHolderForMysql() string {
-	return "?"
+	return dialect, nil
 }
 
-func (rec *QueryType) getTableNamePostgresql(tableBase string, tableAsBase string) (string, string) {
-	table := fmt.Sprintf("\"%v\"", tableBase)
+func (rec *QueryType) getTableName(tableBase string, tableAsBase string) (string, string) {
+	table := rec.dialect.QuoteIdentifier(tableBase)
 	tableAs := table
 	if tableAsBase != "" {
-		tableAs = fmt.Sprintf("\"%v\"", tableBase)
+		tableAs = rec.dialect.QuoteIdentifier(tableAsBase)
 	}
 	return table, tableAs
 }
 
-func (rec *QueryType) getColumnNamePostgresql(table string, tableAs string, columnBase string) (string, string, string) {
-	tableColumn := fmt.Sprintf("\"%v\".\"%v\"", table, columnBase)
-	tableAsColumn := fmt.Sprintf("%v.\"%v\"", tableAs, columnBase)
-	column := fmt.Sprintf("\"%v\"", columnBase)
+func (rec *QueryType) getColumnName(table string, tableAs string, columnBase string) (string, string, string) {
+	column := rec.dialect.QuoteIdentifier(columnBase)
+	tableColumn := fmt.Sprintf("%v.%v", table, column)
+	tableAsColumn := fmt.Sprintf("%v.%v", tableAs, column)
 	return tableColumn, tableAsColumn, column
 }
 
-func (rec *QueryType) getPlaceHolderForPostgresql() string {
-	str := ""
+func (rec *QueryType) getPlaceholder() string {
 	rec.ValuesColumnCount++
-	str = fmt.Sprintf("$%v", rec.ValuesColumnCount)
 
-	return str
+	return rec.dialect.Placeholder(rec.ValuesColumnCount)
+}
+
+func (rec *QueryType) setWith(tablePtr interface{}, query *QueryType, recursiveQuery *QueryType) {
+	withData := &withType{
+		TablePtr:       tablePtr,
+		Query:          query,
+		RecursiveQuery: recursiveQuery,
+	}
+
+	rec.WithList = append(rec.WithList, withData)
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetWith(tablePtr interface{}, query *QueryType) {
+	rec.setWith(tablePtr, query, nil)
+}
+
+func (rec *QueryType) SetWithRecursive(tablePtr interface{}, query *QueryType, recursiveQuery *QueryType) {
+	rec.setWith(tablePtr, query, recursiveQuery)
 }
 
 func (rec *QueryType) setTable(str string, tablePtr interface{}, tableAs string) {
@@ -322,6 +440,59 @@ func (rec *QueryType) SetJoinRightAs(tablePtr interface{}, tableAs string, colum
 	rec.setJoin(joinModeRight, tablePtr, tableAs, columnPtr, whereColumnPtr)
 }
 
+func (rec *QueryType) SetJoinFull(tablePtr interface{}, columnPtr interface{}, whereColumnPtr interface{}) {
+	rec.setJoin(joinModeFull, tablePtr, "", columnPtr, whereColumnPtr)
+}
+
+func (rec *QueryType) SetJoinFullAs(tablePtr interface{}, tableAs string, columnPtr interface{}, whereColumnPtr interface{}) {
+	rec.setJoin(joinModeFull, tablePtr, tableAs, columnPtr, whereColumnPtr)
+}
+
+func (rec *QueryType) SetJoinCross(tablePtr interface{}) {
+	rec.setJoin(joinModeCross, tablePtr, "", nil, nil)
+}
+
+func (rec *QueryType) SetJoinCrossAs(tablePtr interface{}, tableAs string) {
+	rec.setJoin(joinModeCross, tablePtr, tableAs, nil, nil)
+}
+
+// SetJoinOn adds "AND columnPtr = whereColumnPtr" to the join of tablePtr for a composite key.
+func (rec *QueryType) SetJoinOn(tablePtr interface{}, columnPtr interface{}, whereColumnPtr interface{}) {
+	rec.setJoinWhere(queryModeIs, queryPrefixAnd, tablePtr, "", columnPtr, Column(whereColumnPtr))
+}
+
+func (rec *QueryType) setJoinQuery(mode int, tablePtr interface{}, query *QueryType, lateral bool, columnPtr interface{}, whereColumnPtr interface{}) {
+	joinData := &joinType{
+		Mode:           mode,
+		TablePtr:       tablePtr,
+		TableAs:        "",
+		ColumnPtr:      columnPtr,
+		WhereColumnPtr: whereColumnPtr,
+		Query:          query,
+		Lateral:        lateral,
+	}
+
+	rec.JoinList = append(rec.JoinList, joinData)
+	rec.Data = nil
+}
+
+// SetJoinQuery joins query as the table of tablePtr.
+func (rec *QueryType) SetJoinQuery(tablePtr interface{}, query *QueryType, columnPtr interface{}, whereColumnPtr interface{}) {
+	rec.setJoinQuery(joinModeInner, tablePtr, query, false, columnPtr, whereColumnPtr)
+}
+
+func (rec *QueryType) SetJoinLeftQuery(tablePtr interface{}, query *QueryType, columnPtr interface{}, whereColumnPtr interface{}) {
+	rec.setJoinQuery(joinModeLeft, tablePtr, query, false, columnPtr, whereColumnPtr)
+}
+
+func (rec *QueryType) SetJoinLateral(tablePtr interface{}, query *QueryType) {
+	rec.setJoinQuery(joinModeCross, tablePtr, query, true, nil, nil)
+}
+
+func (rec *QueryType) SetJoinLeftLateral(tablePtr interface{}, query *QueryType) {
+	rec.setJoinQuery(joinModeLeft, tablePtr, query, true, nil, nil)
+}
+
 func (rec *QueryType) setJoinWhere(mode int, prefix int, tablePtr interface{}, str string, columnPtr interface{}, valueList ...interface{}) {
 	joinWhereData := &joinWhereType{
 		Mode:      mode,
@@ -502,6 +673,54 @@ func (rec *QueryType) SetSelectAll(tablePtr interface{}) {
 	rec.setSelect(queryModeAll, "", tablePtr, "")
 }
 
+func (rec *QueryType) setSelectOver(format string, columnPtr interface{}, window *WindowType, as string) {
+	selectData := &selectType{
+		Mode:      queryModeFormat,
+		Str:       format,
+		ColumnPtr: columnPtr,
+		ColumnAs:  as,
+		Window:    window,
+	}
+
+	rec.SelectList = append(rec.SelectList, selectData)
+	rec.Data = nil
+}
+
+// SetSelectOver selects "format OVER (window)". format is like "row_number()" or "sum(%s)" with columnPtr.
+func (rec *QueryType) SetSelectOver(format string, columnPtr interface{}, window *WindowType) {
+	rec.setSelectOver(format, columnPtr, window, "")
+}
+
+func (rec *QueryType) SetSelectOverAs(format string, columnPtr interface{}, window *WindowType, as string) {
+	rec.setSelectOver(format, columnPtr, window, as)
+}
+
+func (rec *QueryType) SetSelectQueryAs(query *QueryType, as string) {
+	selectData := &selectType{
+		Mode:     queryModeQuery,
+		ColumnAs: as,
+		Query:    query,
+	}
+
+	rec.SelectList = append(rec.SelectList, selectData)
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetDistinct() {
+	if rec.Distinct == nil {
+		rec.Distinct = &distinctType{}
+	}
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetDistinctOn(columnPtrList ...interface{}) {
+	if rec.Distinct == nil {
+		rec.Distinct = &distinctType{}
+	}
+	rec.Distinct.ColumnPtrList = append(rec.Distinct.ColumnPtrList, columnPtrList...)
+	rec.Data = nil
+}
+
 func (rec *QueryType) SetSet(columnPtr interface{}, value interface{}) {
 	setData := &setType{
 		ColumnPtr: columnPtr,
@@ -540,8 +759,14 @@ func (rec *QueryType) SetValues(valueList ...interface{}) {
 	}
 }
 
+func (rec *QueryType) SetValuesQuery(query *QueryType) {
+	rec.ValuesQuery = query
+	rec.Data = nil
+}
+
 func (rec *QueryType) SetValuesClear() {
 	rec.ValuesList = make([][]*valuesType, 0)
+	rec.ValuesQuery = nil
 	rec.Data = nil
 }
 
@@ -670,6 +895,22 @@ func (rec *QueryType) SetWhereOrLte(columnPtr interface{}, valueList ...interfac
 	rec.setWhere(queryModeLte, queryPrefixOr, "", columnPtr, valueList...)
 }
 
+func (rec *QueryType) SetWhereExists(query *QueryType) {
+	rec.setWhere(queryModeExists, queryPrefixAnd, "", nil, query)
+}
+
+func (rec *QueryType) SetWhereExistsNot(query *QueryType) {
+	rec.setWhere(queryModeExistsNot, queryPrefixAnd, "", nil, query)
+}
+
+func (rec *QueryType) SetWhereOrExists(query *QueryType) {
+	rec.setWhere(queryModeExists, queryPrefixOr, "", nil, query)
+}
+
+func (rec *QueryType) SetWhereOrExistsNot(query *QueryType) {
+	rec.setWhere(queryModeExistsNot, queryPrefixOr, "", nil, query)
+}
+
 func (rec *QueryType) SetWhereNest() {
 	rec.setWhere(queryModeNest, queryPrefixAnd, "", nil)
 }
@@ -892,7 +1133,178 @@ func (rec *QueryType) SetOffset(num int) {
 	rec.Data = nil
 }
 
+func (rec *QueryType) SetWindow(name string, window *WindowType) {
+	windowDefData := &windowDefType{
+		Name:   name,
+		Window: window,
+	}
+
+	rec.WindowList = append(rec.WindowList, windowDefData)
+	rec.Data = nil
+}
+
+func (rec *QueryType) setUnion(mode int, query *QueryType) {
+	unionData := &unionType{
+		Mode:  mode,
+		Query: query,
+	}
+
+	rec.UnionList = append(rec.UnionList, unionData)
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetUnion(query *QueryType) {
+	rec.setUnion(unionModeUnion, query)
+}
+
+func (rec *QueryType) SetUnionAll(query *QueryType) {
+	rec.setUnion(unionModeUnionAll, query)
+}
+
+func (rec *QueryType) SetIntersect(query *QueryType) {
+	rec.setUnion(unionModeIntersect, query)
+}
+
+func (rec *QueryType) SetExcept(query *QueryType) {
+	rec.setUnion(unionModeExcept, query)
+}
+
+func (rec *QueryType) getLock() *lockType {
+	if rec.Lock == nil {
+		rec.Lock = &lockType{
+			Mode: lockModeUpdate,
+			Wait: lockWaitNone,
+		}
+	}
+
+	return rec.Lock
+}
+
+func (rec *QueryType) SetForUpdate() {
+	lockData := rec.getLock()
+	lockData.Mode = lockModeUpdate
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetForNoKeyUpdate() {
+	lockData := rec.getLock()
+	lockData.Mode = lockModeNoKeyUpdate
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetForShare() {
+	lockData := rec.getLock()
+	lockData.Mode = lockModeShare
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetLockOf(tablePtrList ...interface{}) {
+	lockData := rec.getLock()
+	lockData.TablePtrList = append(lockData.TablePtrList, tablePtrList...)
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetLockNowait() {
+	lockData := rec.getLock()
+	lockData.Wait = lockWaitNowait
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetLockSkipLocked() {
+	lockData := rec.getLock()
+	lockData.Wait = lockWaitSkipLocked
+	rec.Data = nil
+}
+
+func (rec *QueryType) getConflict() *conflictType {
+	if rec.Conflict == nil {
+		rec.Conflict = &conflictType{
+			Mode: conflictModeNone,
+		}
+	}
+
+	return rec.Conflict
+}
+
+func (rec *QueryType) SetConflict(columnPtrList ...interface{}) {
+	conflictData := rec.getConflict()
+	conflictData.ColumnPtrList = append(conflictData.ColumnPtrList, columnPtrList...)
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetConflictDoNothing() {
+	conflictData := rec.getConflict()
+	conflictData.Mode = conflictModeNothing
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetConflictUpdate(columnPtrList ...interface{}) {
+	conflictData := rec.getConflict()
+	conflictData.Mode = conflictModeUpdate
+	conflictData.UpdateColumnPtrList = append(conflictData.UpdateColumnPtrList, columnPtrList...)
+	rec.Data = nil
+}
+
+func (rec *QueryType) setConflictWhere(mode int, prefix int, str string, columnPtr interface{}, valueList ...interface{}) {
+	whereData := &whereType{
+		Mode:      mode,
+		Prefix:    prefix,
+		Str:       str,
+		ColumnPtr: columnPtr,
+		ValueList: valueList,
+	}
+
+	rec.ConflictWhereList = append(rec.ConflictWhereList, whereData)
+	rec.Data = nil
+}
+
+func (rec *QueryType) SetConflictWhereString(str string, valueList ...interface{}) {
+	rec.setConflictWhere(queryModeString, queryPrefixAnd, str, nil, valueList...)
+}
+
+func (rec *QueryType) SetConflictWhereFormat(format string, columnPtr interface{}, valueList ...interface{}) {
+	rec.setConflictWhere(queryModeFormat, queryPrefixAnd, format, columnPtr, valueList...)
+}
+
+func (rec *QueryType) SetConflictWhereIs(columnPtr interface{}, value interface{}) {
+	rec.setConflictWhere(queryModeIs, queryPrefixAnd, "", columnPtr, value)
+}
+
+func (rec *QueryType) SetConflictWhereIsNot(columnPtr interface{}, value interface{}) {
+	rec.setConflictWhere(queryModeIsNot, queryPrefixAnd, "", columnPtr, value)
+}
+
+func (rec *QueryType) SetConflictWhereIsNull(columnPtr interface{}) {
+	rec.setConflictWhere(queryModeIsNull, queryPrefixAnd, "", columnPtr)
+}
+
+func (rec *QueryType) SetConflictWhereIsNotNull(columnPtr interface{}) {
+	rec.setConflictWhere(queryModeIsNullNot, queryPrefixAnd, "", columnPtr)
+}
+
+func (rec *QueryType) SetReturning(columnPtrList ...interface{}) {
+	for _, columnPtr := range columnPtrList {
+		returningData := &returningType{
+			ColumnPtr: columnPtr,
+		}
+
+		rec.ReturningList = append(rec.ReturningList, returningData)
+		rec.Data = nil
+	}
+}
+
+// SetLastInsertId sets the AUTO_INCREMENT column. InsertReturning selects the inserted row with it when RETURNING is not supported.
+func (rec *QueryType) SetLastInsertId(columnPtr interface{}) {
+	rec.LastInsertIdPtr = columnPtr
+	rec.Data = nil
+}
+
 func (rec *QueryType) buildMeta() error {
+	_, err := rec.getDialect()
+	if err != nil {
+		return err
+	}
+
 	rec.MetaMap = make(map[string]*metaType, 0)
 	rec.Data = &buildType{}
 	if rec.modeResetAuto {
@@ -959,12 +1371,57 @@ func (rec *QueryType) buildMeta() error {
 		}
 	}
 
+	// columns of the outer query can be used in a correlated subquery
+	if rec.parent != nil {
+		for key, meta := range rec.parent.MetaMap {
+			if _, ok := rec.MetaMap[key]; !ok {
+				rec.MetaMap[key] = meta
+			}
+		}
+	}
+
 	return nil
 }
 
 func (rec *QueryType) buildValue(value interface{}) ([]string, error) {
 	var strList []string
 
+	switch value := value.(type) {
+	case *QueryType:
+		str, err := rec.buildSubQuery(value)
+		if err != nil {
+			return nil, err
+		}
+
+		strList = append(strList, fmt.Sprintf("(%s)", str))
+		return strList, nil
+	case *ExprType:
+		str, err := rec.buildExpr(value)
+		if err != nil {
+			return nil, err
+		}
+
+		strList = append(strList, str)
+		return strList, nil
+	case *ColumnType:
+		addr, err := getAddrFromInterface(value.ColumnPtr)
+		if err != nil {
+			return nil, err
+		}
+
+		meta, ok := rec.MetaMap[addr]
+		if !ok {
+			return nil, errors.New("column meta not exist")
+		}
+
+		if rec.Data.ColumnUnqualified {
+			strList = append(strList, meta.Column)
+		} else {
+			strList = append(strList, meta.TableAsColumn)
+		}
+		return strList, nil
+	}
+
 	val := reflect.ValueOf(value)
 	kind := val.Kind()
 	for kind == reflect.Interface || kind == reflect.Ptr {
@@ -1017,36 +1474,145 @@ func (rec *QueryType) buildValue(value interface{}) ([]string, error) {
 	return strList, nil
 }
 
-func (rec *QueryType) buildTable() error {
-	var str string
-
-	if rec.Table == nil {
-		return errors.New("table not exist")
+// buildSubQuery builds query continuing the placeholder number and the value list.
+func (rec *QueryType) buildSubQuery(query *QueryType) (string, error) {
+	if query == rec {
+		return "", errors.New("subquery is itself")
 	}
 
-	if rec.Table.Str != "" {
-		rec.Data.Table = rec.Table.Str
-		str = fmt.Sprintf("FROM %s", rec.Table.Str)
-		rec.Data.TableForSelect = str
-		return nil
-	}
+	// build a copy, so that the query of the caller is not changed
+	queryData := *query
+	queryData.modeDatabaseType = rec.modeDatabaseType
+	queryData.dialect = rec.dialect
+	queryData.modeResetAuto = false
+	queryData.ValuesColumnCount = rec.ValuesColumnCount
+	queryData.parent = rec
+	queryData.Data = nil
 
-	addr, err := getAddrFromInterface(rec.Table.TablePtr)
+	str, valueList, err := queryData.GetSelectQuery()
 	if err != nil {
-		return err
-	}
-
-	meta, ok := rec.MetaMap[addr]
-	if !ok {
-		return errors.New("table meta not exist")
+		return "", err
 	}
 
-	table := meta.Table
+	rec.ValuesColumnCount = queryData.ValuesColumnCount
+	rec.Data.ValueList = append(rec.Data.ValueList, valueList...)
 
-	rec.Data.Table = fmt.Sprintf("%s", table)
+	return str, nil
+}
 
-	if meta.TableAsBase != "" {
-		table = fmt.Sprintf("%s as %s", str, meta.TableAs)
+// buildExpr replaces each %s of the format without fmt, so the other % like modulo and LIKE 'a%' are kept.
+func (rec *QueryType) buildExpr(expr *ExprType) (string, error) {
+	formatList := strings.Split(expr.Format, "%s")
+	if len(formatList)-1 != len(expr.ArgList) {
+		return "", errors.New("expr argument count is different from the format")
+	}
+
+	strList := []string{formatList[0]}
+
+	for key, arg := range expr.ArgList {
+		valList, err := rec.buildValue(arg)
+		if err != nil {
+			return "", err
+		}
+
+		strList = append(strList, strings.Join(valList, ", "), formatList[key+1])
+	}
+
+	return strings.Join(strList, ""), nil
+}
+
+// isSubQuery is true if valueList is only one subquery. It has the parentheses already.
+func isSubQuery(valueList []interface{}) bool {
+	if len(valueList) != 1 {
+		return false
+	}
+
+	_, ok := valueList[0].(*QueryType)
+	return ok
+}
+
+func (rec *QueryType) buildWith() error {
+	var strList []string
+	recursive := false
+
+	for _, withData := range rec.WithList {
+		if isNil(withData.TablePtr) {
+			return errors.New("with table not exist")
+		}
+
+		if withData.Query == nil {
+			return errors.New("with query not exist")
+		}
+
+		tableType := reflect.TypeOf(withData.TablePtr)
+		if tableType.Kind() != reflect.Ptr || tableType.Elem().Kind() != reflect.Struct {
+			return errors.New("with table is not struct pointer")
+		}
+
+		table, _ := rec.getTableName(toSnakeCase(tableType.Elem().Name()), "")
+
+		str, err := rec.buildSubQuery(withData.Query)
+		if err != nil {
+			return err
+		}
+
+		if withData.RecursiveQuery != nil {
+			recursive = true
+
+			recursiveStr, err := rec.buildSubQuery(withData.RecursiveQuery)
+			if err != nil {
+				return err
+			}
+
+			str = fmt.Sprintf("%s UNION ALL %s", str, recursiveStr)
+		}
+
+		strList = append(strList, fmt.Sprintf("%s AS (%s)", table, str))
+	}
+
+	if len(strList) > 0 {
+		str := "WITH"
+		if recursive {
+			str = "WITH RECURSIVE"
+		}
+		rec.Data.With = fmt.Sprintf("%s %s", str, strings.Join(strList, ", "))
+	}
+
+	return nil
+}
+
+func (rec *QueryType) buildTable() error {
+	var str string
+
+	if rec.Table == nil {
+		return errors.New("table not exist")
+	}
+
+	if rec.Table.Str != "" {
+		rec.Data.Table = rec.Table.Str
+		rec.Data.TableAs = rec.Table.Str
+		str = fmt.Sprintf("FROM %s", rec.Table.Str)
+		rec.Data.TableForSelect = str
+		return nil
+	}
+
+	addr, err := getAddrFromInterface(rec.Table.TablePtr)
+	if err != nil {
+		return err
+	}
+
+	meta, ok := rec.MetaMap[addr]
+	if !ok {
+		return errors.New("table meta not exist")
+	}
+
+	table := meta.Table
+
+	rec.Data.Table = fmt.Sprintf("%s", table)
+	rec.Data.TableAs = meta.TableAs
+
+	if meta.TableAsBase != "" {
+		table = fmt.Sprintf("%s as %s", table, meta.TableAs)
 	}
 
 	rec.Data.TableForSelect = fmt.Sprintf("FROM %s", table)
@@ -1056,6 +1622,10 @@ func (rec *QueryType) buildTable() error {
 
 func (rec *QueryType) buildJoin() error {
 	var strList []string
+	var joinTableList []string
+	var joinOnList []string
+	var joinTableValueList []interface{}
+	var joinOnValueList []interface{}
 
 	var joinWhereMap map[string][]*joinWhereType
 	joinWhereMap = make(map[string][]*joinWhereType)
@@ -1093,7 +1663,8 @@ func (rec *QueryType) buildJoin() error {
 		}
 
 		var metaColumn *metaType
-		{
+		var metaWhere *metaType
+		if !isNil(joinData.ColumnPtr) || !isNil(joinData.WhereColumnPtr) {
 			addr, err := getAddrFromInterface(joinData.ColumnPtr)
 			if err != nil {
 				return err
@@ -1105,16 +1676,13 @@ func (rec *QueryType) buildJoin() error {
 			}
 
 			metaColumn = meta
-		}
 
-		var metaWhere *metaType
-		{
-			addr, err := getAddrFromInterface(joinData.WhereColumnPtr)
+			addr, err = getAddrFromInterface(joinData.WhereColumnPtr)
 			if err != nil {
 				return err
 			}
 
-			meta, ok := rec.MetaMap[addr]
+			meta, ok = rec.MetaMap[addr]
 			if !ok {
 				return errors.New("build join column2 meta not exist")
 			}
@@ -1130,10 +1698,39 @@ func (rec *QueryType) buildJoin() error {
 			prefix = "LEFT"
 		case joinModeRight:
 			prefix = "RIGHT"
+		case joinModeFull:
+			if !rec.dialect.Support(DialectFeatureJoinFull) {
+				return errors.New("full join not supported")
+			}
+			prefix = "FULL OUTER"
+		case joinModeCross:
+			prefix = "CROSS"
 		default:
 			return errors.New("join unknown mode")
 		}
 
+		valueIndex := len(rec.Data.ValueList)
+
+		table := metaTable.Table
+		if joinData.Query != nil {
+			str, err := rec.buildSubQuery(joinData.Query)
+			if err != nil {
+				return err
+			}
+
+			table = fmt.Sprintf("(%s) as %s", str, metaTable.TableAs)
+			if joinData.Lateral {
+				if !rec.dialect.Support(DialectFeatureJoinLateral) {
+					return errors.New("lateral join not supported")
+				}
+				table = fmt.Sprintf("LATERAL %s", table)
+			}
+		} else if metaTable.Table != metaTable.TableAs {
+			table = fmt.Sprintf("%s as %s", table, metaTable.TableAs)
+		}
+
+		joinValueIndex := len(rec.Data.ValueList)
+
 		type dataType struct {
 			Meta  *metaType
 			Base  string
@@ -1210,8 +1807,14 @@ func (rec *QueryType) buildJoin() error {
 					data.Base = "%s NOT LIKE %s"
 				case queryModeIn:
 					data.Base = "%s IN (%s)"
+					if isSubQuery(joinWhereData.ValueList) {
+						data.Base = "%s IN %s"
+					}
 				case queryModeInNot:
 					data.Base = "%s NOT IN (%s)"
+					if isSubQuery(joinWhereData.ValueList) {
+						data.Base = "%s NOT IN %s"
+					}
 				case queryModeGt:
 					data.Base = "%s > %s"
 				case queryModeGte:
@@ -1247,24 +1850,48 @@ func (rec *QueryType) buildJoin() error {
 			}
 		}
 
-		where := metaWhere.TableAsColumn
+		var on string
+		if metaColumn != nil {
+			on = fmt.Sprintf("%s = %s", metaColumn.TableAsColumn, metaWhere.TableAsColumn)
+		} else if joinData.Mode != joinModeCross {
+			// LEFT JOIN LATERAL needs ON
+			on = "true"
+		}
+
 		if len(joinWhereList) > 0 {
+			if on == "" {
+				return errors.New("cross join where not supported")
+			}
+
 			str := strings.Join(joinWhereList, " ")
-			where = fmt.Sprintf("%s %s", where, str)
+			on = fmt.Sprintf("%s %s", on, str)
 		}
 
-		table := metaTable.Table
-		if metaTable.Table != metaTable.TableAs {
-			table = fmt.Sprintf("%s as %s", table, metaTable.TableAs)
+		str := fmt.Sprintf("%s JOIN %s", prefix, table)
+		if on != "" {
+			str = fmt.Sprintf("%s ON %s", str, on)
 		}
 
-		str := fmt.Sprintf("%s JOIN %s ON %s = %s", prefix, table, metaColumn.TableAsColumn, where)
-
 		strList = append(strList, str)
+
+		// for UPDATE ... FROM and DELETE ... USING
+		joinTableList = append(joinTableList, table)
+		joinTableValueList = append(joinTableValueList, rec.Data.ValueList[valueIndex:joinValueIndex]...)
+		joinOnValueList = append(joinOnValueList, rec.Data.ValueList[joinValueIndex:]...)
+		if on != "" {
+			if len(joinWhereList) > 0 {
+				on = fmt.Sprintf("(%s)", on)
+			}
+			joinOnList = append(joinOnList, on)
+		}
 	}
 
 	if len(strList) > 0 {
 		rec.Data.Join = strings.Join(strList, " ")
+		rec.Data.JoinTable = strings.Join(joinTableList, ", ")
+		rec.Data.JoinOn = strings.Join(joinOnList, " AND ")
+		rec.Data.JoinTableValueList = joinTableValueList
+		rec.Data.JoinOnValueList = joinOnValueList
 	}
 
 	return nil
@@ -1311,12 +1938,36 @@ func (rec *QueryType) buildSelect() error {
 			str := fmt.Sprintf("%s.*", data.Meta.TableAs)
 			strList = append(strList, str)
 		case queryModeString:
-			strList = append(strList, selectData.Str)
+			str := selectData.Str
+			if selectData.ColumnAs != "" {
+				str = fmt.Sprintf("%s as \"%s\"", str, selectData.ColumnAs)
+			}
+			strList = append(strList, str)
 		case queryModeFormat:
 			str := selectData.Str
 			if data.Meta != nil {
 				str = fmt.Sprintf(str, data.Meta.TableAsColumn)
 			}
+			if selectData.Window != nil {
+				over, err := rec.buildOver(selectData.Window)
+				if err != nil {
+					return err
+				}
+				str = fmt.Sprintf("%s OVER %s", str, over)
+			}
+			if selectData.ColumnAs != "" {
+				str = fmt.Sprintf("%s as \"%s\"", str, selectData.ColumnAs)
+			}
+			strList = append(strList, str)
+		case queryModeQuery:
+			if selectData.Query == nil {
+				return errors.New("select query not exist")
+			}
+			str, err := rec.buildSubQuery(selectData.Query)
+			if err != nil {
+				return err
+			}
+			str = fmt.Sprintf("(%s)", str)
 			if selectData.ColumnAs != "" {
 				str = fmt.Sprintf("%s as \"%s\"", str, selectData.ColumnAs)
 			}
@@ -1327,12 +1978,119 @@ func (rec *QueryType) buildSelect() error {
 	}
 
 	if len(strList) > 0 {
-		rec.Data.Select = fmt.Sprintf("SELECT %s", strings.Join(strList, ", "))
+		distinct, err := rec.buildDistinct()
+		if err != nil {
+			return err
+		}
+
+		if distinct != "" {
+			rec.Data.Select = fmt.Sprintf("SELECT %s %s", distinct, strings.Join(strList, ", "))
+		} else {
+			rec.Data.Select = fmt.Sprintf("SELECT %s", strings.Join(strList, ", "))
+		}
 	}
 
 	return nil
 }
 
+func (rec *QueryType) buildDistinct() (string, error) {
+	if rec.Distinct == nil {
+		return "", nil
+	}
+
+	if len(rec.Distinct.ColumnPtrList) < 1 {
+		return "DISTINCT", nil
+	}
+
+	if !rec.dialect.Support(DialectFeatureDistinctOn) {
+		return "", errors.New("distinct on not supported")
+	}
+
+	// the first ORDER BY columns must be the DISTINCT ON columns
+	addrMap := make(map[string]bool)
+	var columnList []string
+
+	for _, columnPtr := range rec.Distinct.ColumnPtrList {
+		addr, err := getAddrFromInterface(columnPtr)
+		if err != nil {
+			return "", err
+		}
+
+		meta, ok := rec.MetaMap[addr]
+		if !ok {
+			return "", errors.New("distinct on meta not exist")
+		}
+
+		addrMap[addr] = true
+		columnList = append(columnList, meta.TableAsColumn)
+	}
+
+	if len(rec.OrderByList) > 0 {
+		if len(rec.OrderByList) < len(addrMap) {
+			return "", errors.New("distinct on is different from the first order by")
+		}
+
+		for _, orderByData := range rec.OrderByList[:len(addrMap)] {
+			if orderByData.Mode != queryModeOne {
+				return "", errors.New("distinct on is different from the first order by")
+			}
+
+			addr, err := getAddrFromInterface(orderByData.ColumnPtr)
+			if err != nil {
+				return "", err
+			}
+
+			if !addrMap[addr] {
+				return "", errors.New("distinct on is different from the first order by")
+			}
+		}
+	}
+
+	return fmt.Sprintf("DISTINCT ON (%s)", strings.Join(columnList, ", ")), nil
+}
+
+// isDistinctColumn is true if the query is DISTINCT of one column, which is counted with count(DISTINCT column).
+func (rec *QueryType) isDistinctColumn() bool {
+	if rec.Distinct == nil || len(rec.Distinct.ColumnPtrList) > 0 {
+		return false
+	}
+
+	return len(rec.SelectList) == 1 && rec.SelectList[0].Mode == queryModeOne
+}
+
+// getSelectColumnCount is the number of the select columns. SetSelectString is counted as one column.
+func (rec *QueryType) getSelectColumnCount() (int, error) {
+	count := 0
+
+	for _, selectData := range rec.SelectList {
+		if selectData.Mode != queryModeAll {
+			count++
+			continue
+		}
+
+		if isNil(selectData.ColumnPtr) {
+			return 0, errors.New("select column table not exist")
+		}
+
+		tableType := reflect.TypeOf(selectData.ColumnPtr)
+		for tableType.Kind() == reflect.Ptr {
+			tableType = tableType.Elem()
+		}
+
+		if tableType.Kind() != reflect.Struct {
+			return 0, errors.New("select column table not exist")
+		}
+
+		for i := 0; i < tableType.NumField(); i++ {
+			if tableType.Field(i).Tag.Get(structFieldTagNameColumn) != "" {
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
 func (rec *QueryType) buildValuesColumnAndValues() error {
 	if len(rec.ValuesColumnList) < 1 {
 		return nil
@@ -1364,6 +2122,30 @@ func (rec *QueryType) buildValuesColumnAndValues() error {
 		}
 	}
 
+	if rec.ValuesQuery != nil {
+		if len(rec.ValuesList) > 0 {
+			return errors.New("values and values query can not be used together")
+		}
+
+		selectCount, err := rec.ValuesQuery.getSelectColumnCount()
+		if err != nil {
+			return err
+		}
+
+		if selectCount != valuesColumnCount {
+			return errors.New("values query is different from the number of valuescolumn")
+		}
+
+		str, err := rec.buildSubQuery(rec.ValuesQuery)
+		if err != nil {
+			return err
+		}
+
+		rec.Data.Values = str
+
+		return nil
+	}
+
 	{
 		var valuesList []string
 
@@ -1421,7 +2203,12 @@ func (rec *QueryType) buildSet() error {
 			return errors.New("set value length is not 1")
 		}
 
-		setList = append(setList, fmt.Sprintf("%s = %v", meta.Column, valList[0]))
+		column := meta.Column
+		if len(rec.JoinList) > 0 && rec.dialect.Support(DialectFeatureUpdateJoin) {
+			column = meta.TableAsColumn
+		}
+
+		setList = append(setList, fmt.Sprintf("%s = %v", column, valList[0]))
 	}
 
 	if len(setList) > 0 {
@@ -1513,8 +2300,14 @@ func (rec *QueryType) buildWhere() error {
 			data.Base = "%s NOT LIKE %s"
 		case queryModeIn:
 			data.Base = "%s IN (%s)"
+			if isSubQuery(whereData.ValueList) {
+				data.Base = "%s IN %s"
+			}
 		case queryModeInNot:
 			data.Base = "%s NOT IN (%s)"
+			if isSubQuery(whereData.ValueList) {
+				data.Base = "%s NOT IN %s"
+			}
 		case queryModeGt:
 			data.Base = "%s > %s"
 		case queryModeGte:
@@ -1528,6 +2321,10 @@ func (rec *QueryType) buildWhere() error {
 			prefixFlag = false
 		case queryModeNestClose:
 			data.Base = ")"
+		case queryModeExists:
+			data.Base = "EXISTS %s"
+		case queryModeExistsNot:
+			data.Base = "NOT EXISTS %s"
 		default:
 			return errors.New("where type not exist")
 		}
@@ -1695,8 +2492,14 @@ func (rec *QueryType) buildHaving() error {
 				data.Base = "%s NOT LIKE %s"
 			case queryModeIn:
 				data.Base = "%s IN (%s)"
+				if isSubQuery(havingData.ValueList) {
+					data.Base = "%s IN %s"
+				}
 			case queryModeInNot:
 				data.Base = "%s NOT IN (%s)"
+				if isSubQuery(havingData.ValueList) {
+					data.Base = "%s NOT IN %s"
+				}
 			case queryModeGt:
 				data.Base = "%s > %s"
 			case queryModeGte:
@@ -1739,326 +2542,522 @@ func (rec *QueryType) buildHaving() error {
 	return nil
 }
 
-func (rec *QueryType) buildOrderBy() error {
-	var orderByList []string
+// buildOver builds "(PARTITION BY ... ORDER BY ... frame)" or the name of WindowName.
+func (rec *QueryType) buildOver(window *WindowType) (string, error) {
+	var strList []string
 
-	type dataType struct {
-		Meta *metaType
-		Base string
+	if window.Name != "" {
+		strList = append(strList, rec.dialect.QuoteIdentifier(window.Name))
 	}
 
-	for _, orderByData := range rec.OrderByList {
-		data := &dataType{}
+	if len(window.PartitionByList) > 0 {
+		var partitionByList []string
 
-		if !isNil(orderByData.ColumnPtr) {
-			addr, err := getAddrFromInterface(orderByData.ColumnPtr)
+		for _, columnPtr := range window.PartitionByList {
+			addr, err := getAddrFromInterface(columnPtr)
 			if err != nil {
-				return err
+				return "", err
 			}
 
 			meta, ok := rec.MetaMap[addr]
 			if !ok {
-				return errors.New("build order meta not exist")
+				return "", errors.New("window partition by meta not exist")
 			}
 
-			data.Meta = meta
+			partitionByList = append(partitionByList, meta.TableAsColumn)
 		}
 
-		switch orderByData.Mode {
-		case queryModeOne:
-			data.Base = "%s"
-		case queryModeString:
-			data.Base = orderByData.Str
-		case queryModeFormat:
-			data.Base = orderByData.Str
-		default:
-			return errors.New("orderBy mode not exist")
-		}
+		strList = append(strList, fmt.Sprintf("PARTITION BY %s", strings.Join(partitionByList, ", ")))
+	}
 
-		str := data.Base
-		if data.Meta != nil {
-			str = fmt.Sprintf(str, data.Meta.TableAsColumn)
-		}
-		if orderByData.Order == Desc {
-			str = fmt.Sprintf("%s DESC", str)
-		}
+	if len(window.OrderByList) > 0 {
+		var orderByList []string
 
-		orderByList = append(orderByList, str)
-	}
+		for _, orderByData := range window.OrderByList {
+			addr, err := getAddrFromInterface(orderByData.ColumnPtr)
+			if err != nil {
+				return "", err
+			}
 
-	if len(orderByList) > 0 {
-		rec.Data.Order = fmt.Sprintf("ORDER BY %s", strings.Join(orderByList, ", "))
-	}
+			meta, ok := rec.MetaMap[addr]
+			if !ok {
+				return "", errors.New("window order by meta not exist")
+			}
 
-	return nil
-}
+			str := meta.TableAsColumn
+			if orderByData.Order == Desc {
+				str = fmt.Sprintf("%s DESC", str)
+			}
 
-func (rec *QueryType) buildLimit() error {
-	if rec.Limit > 0 {
-		rec.Data.Limit = fmt.Sprintf("LIMIT %v", rec.Limit)
+			orderByList = append(orderByList, str)
+		}
+
+		strList = append(strList, fmt.Sprintf("ORDER BY %s", strings.Join(orderByList, ", ")))
 	}
 
-	return nil
-}
+	if window.Frame != "" {
+		strList = append(strList, window.Frame)
+	}
 
-func (rec *QueryType) buildOffset() error {
-	if rec.Offset > 0 {
-		rec.Data.Offset = fmt.Sprintf("OFFSET %v", rec.Offset)
+	// OVER name
+	if window.Name != "" && len(strList) == 1 {
+		return strList[0], nil
 	}
 
-	return nil
+	return fmt.Sprintf("(%s)", strings.Join(strList, " ")), nil
 }
 
-func (rec *QueryType) GetSelectQuery() (string, []interface{}, error) {
-	var err error
-	query := ""
+func (rec *QueryType) buildWindow() error {
+	var windowList []string
 
-	err = rec.buildMeta()
-	if err != nil {
-		return "", nil, err
-	}
+	for _, windowDefData := range rec.WindowList {
+		if windowDefData.Window == nil {
+			return errors.New("window not exist")
+		}
 
-	{
-		err = rec.buildSelect()
+		over, err := rec.buildOver(windowDefData.Window)
 		if err != nil {
-			return "", nil, err
+			return err
 		}
 
-		str := rec.Data.Select
-		if str == "" {
-			return "", nil, errors.New("select not exist")
+		if windowDefData.Window.Name != "" && !strings.HasPrefix(over, "(") {
+			over = fmt.Sprintf("(%s)", over)
 		}
-		query = rec.Data.Select
-	}
 
-	{
-		err = rec.buildTable()
-		if err != nil {
-			return "", nil, err
-		}
+		windowList = append(windowList, fmt.Sprintf("%s AS %s", rec.dialect.QuoteIdentifier(windowDefData.Name), over))
+	}
 
-		str := rec.Data.TableForSelect
-		if str == "" {
-			return "", nil, errors.New("select table not exist")
-		}
-		query = fmt.Sprintf("%s %s", query, str)
+	if len(windowList) > 0 {
+		rec.Data.Window = fmt.Sprintf("WINDOW %s", strings.Join(windowList, ", "))
 	}
 
-	{
-		err = rec.buildJoin()
-		if err != nil {
-			return "", nil, err
-		}
+	return nil
+}
 
-		str := rec.Data.Join
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
-		}
-	}
+func (rec *QueryType) buildUnion() error {
+	var strList []string
 
-	{
-		err = rec.buildWhere()
-		if err != nil {
-			return "", nil, err
+	for _, unionData := range rec.UnionList {
+		if unionData.Query == nil {
+			return errors.New("union query not exist")
 		}
 
-		str := rec.Data.WhereForSelect
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
+		switch unionData.Mode {
+		case unionModeUnion:
+			strList = append(strList, "UNION")
+		case unionModeUnionAll:
+			strList = append(strList, "UNION ALL")
+		case unionModeIntersect:
+			strList = append(strList, "INTERSECT")
+		case unionModeExcept:
+			strList = append(strList, "EXCEPT")
+		default:
+			return errors.New("union mode not exist")
 		}
-	}
 
-	{
-		err = rec.buildGroupBy()
+		str, err := rec.buildSubQuery(unionData.Query)
 		if err != nil {
-			return "", nil, err
+			return err
 		}
 
-		str := rec.Data.GroupBy
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
+		// ORDER BY and LIMIT of the query are only for the query
+		if len(unionData.Query.OrderByList) > 0 || unionData.Query.Limit > 0 || unionData.Query.Offset > 0 {
+			if rec.dialect.Support(DialectFeatureUnionParentheses) {
+				str = fmt.Sprintf("(%s)", str)
+			} else {
+				str = fmt.Sprintf("SELECT * FROM (%s)", str)
+			}
 		}
-	}
 
-	{
-		err = rec.buildHaving()
-		if err != nil {
-			return "", nil, err
-		}
+		strList = append(strList, str)
+	}
 
-		str := rec.Data.Having
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
-		}
+	if len(strList) > 0 {
+		rec.Data.Union = strings.Join(strList, " ")
 	}
 
-	{
-		err = rec.buildOrderBy()
-		if err != nil {
-			return "", nil, err
-		}
+	return nil
+}
 
-		str := rec.Data.Order
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
-		}
+func (rec *QueryType) buildOrderBy() error {
+	var orderByList []string
+
+	type dataType struct {
+		Meta *metaType
+		Base string
 	}
 
-	{
-		err = rec.buildLimit()
-		if err != nil {
-			return "", nil, err
-		}
+	for _, orderByData := range rec.OrderByList {
+		data := &dataType{}
 
-		str := rec.Data.Limit
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
+		if !isNil(orderByData.ColumnPtr) {
+			addr, err := getAddrFromInterface(orderByData.ColumnPtr)
+			if err != nil {
+				return err
+			}
+
+			meta, ok := rec.MetaMap[addr]
+			if !ok {
+				return errors.New("build order meta not exist")
+			}
+
+			data.Meta = meta
 		}
-	}
 
-	{
-		err = rec.buildOffset()
-		if err != nil {
-			return "", nil, err
+		switch orderByData.Mode {
+		case queryModeOne:
+			data.Base = "%s"
+		case queryModeString:
+			data.Base = orderByData.Str
+		case queryModeFormat:
+			data.Base = orderByData.Str
+		default:
+			return errors.New("orderBy mode not exist")
 		}
 
-		str := rec.Data.Offset
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
+		str := data.Base
+		if data.Meta != nil {
+			// ORDER BY of UNION is for the result columns
+			if len(rec.UnionList) > 0 {
+				str = fmt.Sprintf(str, data.Meta.Column)
+			} else {
+				str = fmt.Sprintf(str, data.Meta.TableAsColumn)
+			}
+		}
+		if orderByData.Order == Desc {
+			str = fmt.Sprintf("%s DESC", str)
 		}
+
+		orderByList = append(orderByList, str)
 	}
 
-	valueList := rec.Data.ValueList
+	if len(orderByList) > 0 {
+		rec.Data.Order = fmt.Sprintf("ORDER BY %s", strings.Join(orderByList, ", "))
+	}
 
-	return query, valueList, nil
+	return nil
 }
 
-func (rec *QueryType) GetSelectCountQuery() (string, []interface{}, error) {
-	var err error
-	query := ""
+func (rec *QueryType) buildLimit() error {
+	rec.Data.Limit = rec.dialect.LimitOffset(rec.Limit, rec.Offset)
 
-	err = rec.buildMeta()
-	if err != nil {
-		return "", nil, err
-	}
+	return nil
+}
 
-	query = "SELECT count(*)"
+func (rec *QueryType) buildLock() error {
+	if rec.Lock == nil {
+		return nil
+	}
 
-	{
-		err = rec.buildTable()
-		if err != nil {
-			return "", nil, err
-		}
+	if !rec.dialect.Support(DialectFeatureLock) {
+		return errors.New("lock not supported")
+	}
 
-		str := rec.Data.TableForSelect
-		if str == "" {
-			return "", nil, errors.New("select table not exist")
-		}
-		query = fmt.Sprintf("%s %s", query, str)
+	if len(rec.UnionList) > 0 {
+		return errors.New("lock with union not supported")
 	}
 
-	{
-		err = rec.buildJoin()
-		if err != nil {
-			return "", nil, err
-		}
+	var strList []string
 
-		str := rec.Data.Join
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
+	switch rec.Lock.Mode {
+	case lockModeUpdate:
+		strList = append(strList, "FOR UPDATE")
+	case lockModeNoKeyUpdate:
+		if !rec.dialect.Support(DialectFeatureLockNoKeyUpdate) {
+			return errors.New("lock no key update not supported")
 		}
+		strList = append(strList, "FOR NO KEY UPDATE")
+	case lockModeShare:
+		strList = append(strList, "FOR SHARE")
+	default:
+		return errors.New("lock mode not exist")
 	}
 
-	{
-		err = rec.buildWhere()
-		if err != nil {
-			return "", nil, err
-		}
+	if len(rec.Lock.TablePtrList) > 0 {
+		var tableList []string
 
-		str := rec.Data.WhereForSelect
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
-		}
-	}
+		for _, tablePtr := range rec.Lock.TablePtrList {
+			addr, err := getAddrFromInterface(tablePtr)
+			if err != nil {
+				return err
+			}
 
-	{
-		err = rec.buildGroupBy()
-		if err != nil {
-			return "", nil, err
-		}
+			meta, ok := rec.MetaMap[addr]
+			if !ok {
+				return errors.New("lock table meta not exist")
+			}
 
-		str := rec.Data.GroupBy
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
+			tableList = append(tableList, meta.TableAs)
 		}
-	}
 
-	{
-		err = rec.buildHaving()
-		if err != nil {
-			return "", nil, err
-		}
+		strList = append(strList, fmt.Sprintf("OF %s", strings.Join(tableList, ", ")))
+	}
 
-		str := rec.Data.Having
-		if str != "" {
-			query = fmt.Sprintf("%s %s", query, str)
-		}
+	switch rec.Lock.Wait {
+	case lockWaitNone:
+	case lockWaitNowait:
+		strList = append(strList, "NOWAIT")
+	case lockWaitSkipLocked:
+		strList = append(strList, "SKIP LOCKED")
+	default:
+		return errors.New("lock wait not exist")
 	}
 
-	valueList := rec.Data.ValueList
+	rec.Data.Lock = strings.Join(strList, " ")
 
-	return query, valueList, nil
+	return nil
 }
 
-func (rec *QueryType) GetInsertQuery() (string, []interface{}, error) {
-	var err error
-	query := ""
-
-	err = rec.buildMeta()
-	if err != nil {
-		return "", nil, err
+func (rec *QueryType) buildConflict() error {
+	if rec.Conflict == nil {
+		return nil
 	}
 
-	query = "INSERT INTO"
+	getColumnList := func(columnPtrList []interface{}) ([]*metaType, error) {
+		var metaList []*metaType
 
-	{
-		err = rec.buildTable()
-		if err != nil {
-			return "", nil, err
-		}
+		for _, columnPtr := range columnPtrList {
+			addr, err := getAddrFromInterface(columnPtr)
+			if err != nil {
+				return nil, err
+			}
 
-		str := rec.Data.Table
-		if str == "" {
-			return "", nil, errors.New("table not exist")
+			meta, ok := rec.MetaMap[addr]
+			if !ok {
+				return nil, errors.New("conflict meta not exist")
+			}
+
+			metaList = append(metaList, meta)
 		}
-		query = fmt.Sprintf("%s %s", query, str)
+
+		return metaList, nil
 	}
 
-	{
-		err = rec.buildValuesColumnAndValues()
-		if err != nil {
-			return "", nil, err
-		}
+	columnMetaList, err := getColumnList(rec.Conflict.ColumnPtrList)
+	if err != nil {
+		return err
+	}
 
-		str := rec.Data.ValuesColumn
-		if str == "" {
-			return "", nil, errors.New("valuesColumn not exist")
-		}
-		query = fmt.Sprintf("%s %s", query, str)
+	updateMetaList, err := getColumnList(rec.Conflict.UpdateColumnPtrList)
+	if err != nil {
+		return err
+	}
 
-		str = rec.Data.Values
-		if str == "" {
-			return "", nil, errors.New("values not exist")
-		}
-		query = fmt.Sprintf("%s %s", query, str)
+	if rec.Conflict.Mode == conflictModeUpdate && len(updateMetaList) < 1 {
+		return errors.New("conflict update column not exist")
 	}
 
-	valueList := rec.Data.ValueList
+	var where string
+	{
+		var whereList []string
 
-	return query, valueList, nil
+		type dataType struct {
+			Meta  *metaType
+			Base  string
+			Value string
+		}
+		prefixFlag := false
+
+		for _, whereData := range rec.ConflictWhereList {
+			var strList []string
+
+			data := &dataType{}
+
+			if !isNil(whereData.ColumnPtr) {
+				addr, err := getAddrFromInterface(whereData.ColumnPtr)
+				if err != nil {
+					return err
+				}
+
+				meta, ok := rec.MetaMap[addr]
+				if !ok {
+					return errors.New("conflict where meta not exist")
+				}
+
+				data.Meta = meta
+			}
+
+			if len(whereData.ValueList) > 0 {
+				var strList []string
+
+				for _, val := range whereData.ValueList {
+					valList, err := rec.buildValue(val)
+					if err != nil {
+						return err
+					}
+
+					strList = append(strList, valList...)
+				}
+
+				if len(strList) > 0 {
+					data.Value = strings.Join(strList, ", ")
+				}
+			}
+
+			if prefixFlag {
+				switch whereData.Prefix {
+				case queryPrefixAnd:
+					strList = append(strList, "AND")
+				case queryPrefixOr:
+					strList = append(strList, "OR")
+				}
+			} else {
+				prefixFlag = true
+			}
+
+			switch whereData.Mode {
+			case queryModeString:
+				data.Base = whereData.Str
+			case queryModeFormat:
+				data.Base = whereData.Str
+			case queryModeIs:
+				data.Base = "%s = %s"
+			case queryModeIsNot:
+				data.Base = "%s != %s"
+			case queryModeIsNull:
+				data.Base = "%s IS NULL"
+			case queryModeIsNullNot:
+				data.Base = "%s IS NOT NULL"
+			default:
+				return errors.New("conflict where type not exist")
+			}
+
+			if data.Meta != nil {
+				if data.Value != "" {
+					strList = append(strList, fmt.Sprintf(data.Base, data.Meta.TableAsColumn, data.Value))
+				} else {
+					strList = append(strList, fmt.Sprintf(data.Base, data.Meta.TableAsColumn))
+				}
+			} else {
+				if data.Value != "" {
+					strList = append(strList, fmt.Sprintf(data.Base, data.Value))
+				} else {
+					strList = append(strList, data.Base)
+				}
+			}
+
+			whereList = append(whereList, strings.Join(strList, " "))
+		}
+
+		if len(whereList) > 0 {
+			where = fmt.Sprintf("WHERE %s", strings.Join(whereList, " "))
+		}
+	}
+
+	switch {
+	case rec.dialect.Support(DialectFeatureOnConflict):
+		var target string
+		if len(columnMetaList) > 0 {
+			var strList []string
+			for _, meta := range columnMetaList {
+				strList = append(strList, meta.Column)
+			}
+			target = fmt.Sprintf(" (%s)", strings.Join(strList, ", "))
+		}
+
+		switch rec.Conflict.Mode {
+		case conflictModeNothing:
+			rec.Data.Conflict = fmt.Sprintf("ON CONFLICT%s DO NOTHING", target)
+		case conflictModeUpdate:
+			if target == "" {
+				return errors.New("conflict column not exist")
+			}
+
+			var strList []string
+			for _, meta := range updateMetaList {
+				strList = append(strList, fmt.Sprintf("%s = EXCLUDED.%s", meta.Column, meta.Column))
+			}
+
+			rec.Data.Conflict = fmt.Sprintf("ON CONFLICT%s DO UPDATE SET %s", target, strings.Join(strList, ", "))
+			if where != "" {
+				rec.Data.Conflict = fmt.Sprintf("%s %s", rec.Data.Conflict, where)
+			}
+		default:
+			return errors.New("conflict mode not exist")
+		}
+	case rec.dialect.Support(DialectFeatureOnDuplicateKey):
+		if where != "" {
+			return errors.New("conflict where not supported")
+		}
+
+		var strList []string
+		switch rec.Conflict.Mode {
+		case conflictModeNothing:
+			// update nothing with "column = column"
+			metaList := append(columnMetaList, updateMetaList...)
+			if len(metaList) < 1 {
+				for _, valuesColumnData := range rec.ValuesColumnList {
+					addr, err := getAddrFromInterface(valuesColumnData.ColumnPtr)
+					if err != nil {
+						return err
+					}
+
+					meta, ok := rec.MetaMap[addr]
+					if !ok {
+						return errors.New("conflict meta not exist")
+					}
+
+					metaList = append(metaList, meta)
+					break
+				}
+			}
+			if len(metaList) < 1 {
+				return errors.New("conflict column not exist")
+			}
+
+			strList = append(strList, fmt.Sprintf("%s = %s", metaList[0].Column, metaList[0].Column))
+		case conflictModeUpdate:
+			for _, meta := range updateMetaList {
+				strList = append(strList, fmt.Sprintf("%s = VALUES(%s)", meta.Column, meta.Column))
+			}
+		default:
+			return errors.New("conflict mode not exist")
+		}
+
+		rec.Data.Conflict = fmt.Sprintf("ON DUPLICATE KEY UPDATE %s", strings.Join(strList, ", "))
+	default:
+		return errors.New("conflict not supported")
+	}
+
+	return nil
 }
 
-func (rec *QueryType) GetUpdateQuery() (string, []interface{}, error) {
+func (rec *QueryType) buildReturning() error {
+	if len(rec.ReturningList) < 1 {
+		return nil
+	}
+
+	if !rec.dialect.Support(DialectFeatureReturning) {
+		return errors.New("returning not supported")
+	}
+
+	var returningList []string
+
+	for _, returningData := range rec.ReturningList {
+		addr, err := getAddrFromInterface(returningData.ColumnPtr)
+		if err != nil {
+			return err
+		}
+
+		meta, ok := rec.MetaMap[addr]
+		if !ok {
+			return errors.New("returning meta not exist")
+		}
+
+		column := meta.Column
+		if len(rec.JoinList) > 0 {
+			column = meta.TableAsColumn
+		}
+
+		returningList = append(returningList, column)
+	}
+
+	rec.Data.Returning = fmt.Sprintf("RETURNING %s", strings.Join(returningList, ", "))
+
+	return nil
+}
+
+func (rec *QueryType) GetSelectQuery() (string, []interface{}, error) {
 	var err error
 	query := ""
 
@@ -2067,137 +3066,1460 @@ func (rec *QueryType) GetUpdateQuery() (string, []interface{}, error) {
 		return "", nil, err
 	}
 
-	query = "UPDATE"
+	{
+		err = rec.buildWith()
+		if err != nil {
+			return "", nil, err
+		}
+
+		query = rec.Data.With
+	}
 
 	{
-		err = rec.buildTable()
+		err = rec.buildSelect()
 		if err != nil {
 			return "", nil, err
 		}
 
-		str := rec.Data.Table
+		str := rec.Data.Select
 		if str == "" {
-			return "", nil, errors.New("table not exist")
+			return "", nil, errors.New("select not exist")
 		}
-		query = fmt.Sprintf("%s %s", query, str)
+		query = strings.TrimSpace(fmt.Sprintf("%s %s", query, str))
 	}
 
 	{
-		err = rec.buildSet()
+		err = rec.buildTable()
 		if err != nil {
 			return "", nil, err
 		}
 
-		str := rec.Data.Set
+		str := rec.Data.TableForSelect
 		if str == "" {
-			return "", nil, errors.New("set not exist")
+			return "", nil, errors.New("select table not exist")
 		}
 		query = fmt.Sprintf("%s %s", query, str)
 	}
 
-	{
-		err = rec.buildWhere()
-		if err != nil {
-			return "", nil, err
-		}
+	{
+		err = rec.buildJoin()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Join
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildWhere()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.WhereForSelect
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildGroupBy()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.GroupBy
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildHaving()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Having
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildWindow()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Window
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildUnion()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Union
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildOrderBy()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Order
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildLimit()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Limit
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildLock()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Lock
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	valueList := rec.Data.ValueList
+
+	return query, valueList, nil
+}
+
+func (rec *QueryType) GetSelectCountQuery() (string, []interface{}, error) {
+	var err error
+	query := ""
+
+	// count the rows of the whole UNION or DISTINCT. order by, limit, offset and lock are ignored as the other count
+	if len(rec.UnionList) > 0 || (rec.Distinct != nil && !rec.isDistinctColumn()) {
+		queryData := *rec
+		queryData.OrderByList = nil
+		queryData.Limit = 0
+		queryData.Offset = 0
+		queryData.Lock = nil
+		queryData.Data = nil
+
+		query, valueList, err := queryData.GetSelectQuery()
+		if err != nil {
+			return "", nil, err
+		}
+
+		query = fmt.Sprintf("SELECT count(*) FROM (%s) as %s", query, queryData.dialect.QuoteIdentifier("count_result"))
+
+		return query, valueList, nil
+	}
+
+	err = rec.buildMeta()
+	if err != nil {
+		return "", nil, err
+	}
+
+	{
+		err = rec.buildWith()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := "count(*)"
+		if rec.isDistinctColumn() {
+			addr, err := getAddrFromInterface(rec.SelectList[0].ColumnPtr)
+			if err != nil {
+				return "", nil, err
+			}
+
+			meta, ok := rec.MetaMap[addr]
+			if !ok {
+				return "", nil, errors.New("select column meta not exist")
+			}
+
+			str = fmt.Sprintf("count(DISTINCT %s)", meta.TableAsColumn)
+		}
+
+		query = strings.TrimSpace(fmt.Sprintf("%s SELECT %s", rec.Data.With, str))
+	}
+
+	{
+		err = rec.buildTable()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.TableForSelect
+		if str == "" {
+			return "", nil, errors.New("select table not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildJoin()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Join
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildWhere()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.WhereForSelect
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildGroupBy()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.GroupBy
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildHaving()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Having
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	valueList := rec.Data.ValueList
+
+	return query, valueList, nil
+}
+
+func (rec *QueryType) GetInsertQuery() (string, []interface{}, error) {
+	var err error
+	query := ""
+
+	err = rec.buildMeta()
+	if err != nil {
+		return "", nil, err
+	}
+
+	query = "INSERT INTO"
+
+	{
+		err = rec.buildTable()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Table
+		if str == "" {
+			return "", nil, errors.New("table not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildValuesColumnAndValues()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.ValuesColumn
+		if str == "" {
+			return "", nil, errors.New("valuesColumn not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+
+		str = rec.Data.Values
+		if str == "" {
+			return "", nil, errors.New("values not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildConflict()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Conflict
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	{
+		err = rec.buildReturning()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Returning
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	valueList := rec.Data.ValueList
+
+	return query, valueList, nil
+}
+
+func (rec *QueryType) GetUpdateQuery() (string, []interface{}, error) {
+	var err error
+	query := ""
+
+	err = rec.buildMeta()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(rec.JoinList) > 0 {
+		switch {
+		case rec.dialect.Support(DialectFeatureUpdateFrom):
+			return rec.getUpdateFromQuery()
+		case rec.dialect.Support(DialectFeatureUpdateJoin):
+			return rec.getUpdateJoinQuery()
+		default:
+			return "", nil, errors.New("update join not supported")
+		}
+	}
+	rec.Data.ColumnUnqualified = true
+
+	query = "UPDATE"
+
+	{
+		err = rec.buildTable()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Table
+		if str == "" {
+			return "", nil, errors.New("table not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildSet()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Set
+		if str == "" {
+			return "", nil, errors.New("set not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildWhere()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Where
+		if str == "" {
+			return "", nil, errors.New("where not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildReturning()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Returning
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	valueList := rec.Data.ValueList
+
+	return query, valueList, nil
+}
+
+// getUpdateFromQuery is UPDATE table SET ... FROM joinTable WHERE joinOn AND (where).
+func (rec *QueryType) getUpdateFromQuery() (string, []interface{}, error) {
+	var err error
+	query := ""
+
+	err = rec.checkJoinModeInner()
+	if err != nil {
+		return "", nil, err
+	}
+
+	query = "UPDATE"
+
+	{
+		err = rec.buildTable()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.getTableWithAs()
+		if str == "" {
+			return "", nil, errors.New("table not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildSet()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Set
+		if str == "" {
+			return "", nil, errors.New("set not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		valueIndex := len(rec.Data.ValueList)
+
+		err = rec.buildJoin()
+		if err != nil {
+			return "", nil, err
+		}
+
+		query = fmt.Sprintf("%s FROM %s", query, rec.Data.JoinTable)
+
+		// every table comes before the join conditions in WHERE, so the values are reordered the same way
+		rec.Data.ValueList = rec.Data.ValueList[:valueIndex]
+		rec.Data.ValueList = append(rec.Data.ValueList, rec.Data.JoinTableValueList...)
+		rec.Data.ValueList = append(rec.Data.ValueList, rec.Data.JoinOnValueList...)
+	}
+
+	{
+		err = rec.buildWhere()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.getWhereWithJoinOn()
+		if str == "" {
+			return "", nil, errors.New("where not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildReturning()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Returning
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	valueList := rec.Data.ValueList
+
+	return query, valueList, nil
+}
+
+// getUpdateJoinQuery is UPDATE table JOIN ... SET ... WHERE.
+func (rec *QueryType) getUpdateJoinQuery() (string, []interface{}, error) {
+	var err error
+	query := ""
+
+	query = "UPDATE"
+
+	{
+		err = rec.buildTable()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.getTableWithAs()
+		if str == "" {
+			return "", nil, errors.New("table not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildJoin()
+		if err != nil {
+			return "", nil, err
+		}
+
+		query = fmt.Sprintf("%s %s", query, rec.Data.Join)
+	}
+
+	{
+		err = rec.buildSet()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Set
+		if str == "" {
+			return "", nil, errors.New("set not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildWhere()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.WhereForSelect
+		if str == "" {
+			return "", nil, errors.New("where not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildReturning()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Returning
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	valueList := rec.Data.ValueList
+
+	return query, valueList, nil
+}
+
+func (rec *QueryType) GetDeleteQuery() (string, []interface{}, error) {
+	var err error
+	query := ""
+
+	err = rec.buildMeta()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(rec.JoinList) > 0 {
+		switch {
+		case rec.dialect.Support(DialectFeatureDeleteUsing):
+			return rec.getDeleteUsingQuery()
+		case rec.dialect.Support(DialectFeatureDeleteJoin):
+			return rec.getDeleteJoinQuery()
+		default:
+			return "", nil, errors.New("delete join not supported")
+		}
+	}
+	rec.Data.ColumnUnqualified = true
+
+	query = "DELETE FROM"
+
+	{
+		err = rec.buildTable()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Table
+		if str == "" {
+			return "", nil, errors.New("table not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildWhere()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Where
+		if str == "" {
+			return "", nil, errors.New("where not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildReturning()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Returning
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	valueList := rec.Data.ValueList
+
+	return query, valueList, nil
+}
+
+// getDeleteUsingQuery is DELETE FROM table USING joinTable WHERE joinOn AND (where).
+func (rec *QueryType) getDeleteUsingQuery() (string, []interface{}, error) {
+	var err error
+	query := ""
+
+	err = rec.checkJoinModeInner()
+	if err != nil {
+		return "", nil, err
+	}
+
+	query = "DELETE FROM"
+
+	{
+		err = rec.buildTable()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.getTableWithAs()
+		if str == "" {
+			return "", nil, errors.New("table not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		valueIndex := len(rec.Data.ValueList)
+
+		err = rec.buildJoin()
+		if err != nil {
+			return "", nil, err
+		}
+
+		query = fmt.Sprintf("%s USING %s", query, rec.Data.JoinTable)
+
+		// every table comes before the join conditions in WHERE, so the values are reordered the same way
+		rec.Data.ValueList = rec.Data.ValueList[:valueIndex]
+		rec.Data.ValueList = append(rec.Data.ValueList, rec.Data.JoinTableValueList...)
+		rec.Data.ValueList = append(rec.Data.ValueList, rec.Data.JoinOnValueList...)
+	}
+
+	{
+		err = rec.buildWhere()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.getWhereWithJoinOn()
+		if str == "" {
+			return "", nil, errors.New("where not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildReturning()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Returning
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	valueList := rec.Data.ValueList
+
+	return query, valueList, nil
+}
+
+// getDeleteJoinQuery is DELETE table FROM table JOIN ... WHERE.
+func (rec *QueryType) getDeleteJoinQuery() (string, []interface{}, error) {
+	var err error
+	query := ""
+
+	{
+		err = rec.buildTable()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.getTableWithAs()
+		if str == "" {
+			return "", nil, errors.New("table not exist")
+		}
+		query = fmt.Sprintf("DELETE %s FROM %s", rec.Data.TableAs, str)
+	}
+
+	{
+		err = rec.buildJoin()
+		if err != nil {
+			return "", nil, err
+		}
+
+		query = fmt.Sprintf("%s %s", query, rec.Data.Join)
+	}
+
+	{
+		err = rec.buildWhere()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.WhereForSelect
+		if str == "" {
+			return "", nil, errors.New("where not exist")
+		}
+		query = fmt.Sprintf("%s %s", query, str)
+	}
+
+	{
+		err = rec.buildReturning()
+		if err != nil {
+			return "", nil, err
+		}
+
+		str := rec.Data.Returning
+		if str != "" {
+			query = fmt.Sprintf("%s %s", query, str)
+		}
+	}
+
+	valueList := rec.Data.ValueList
+
+	return query, valueList, nil
+}
+
+// checkJoinModeInner checks the joins of UPDATE ... FROM and DELETE ... USING, which are inner or cross join only.
+func (rec *QueryType) checkJoinModeInner() error {
+	for _, joinData := range rec.JoinList {
+		if joinData.Mode != joinModeInner && joinData.Mode != joinModeCross {
+			return errors.New("join mode not supported")
+		}
+	}
+
+	return nil
+}
+
+func (rec *QueryType) getTableWithAs() string {
+	if rec.Data.Table != rec.Data.TableAs {
+		return fmt.Sprintf("%s as %s", rec.Data.Table, rec.Data.TableAs)
+	}
+
+	return rec.Data.Table
+}
+
+func (rec *QueryType) getWhereWithJoinOn() string {
+	if rec.Data.WhereForSelect == "" {
+		return ""
+	}
+
+	if rec.Data.JoinOn == "" {
+		return rec.Data.WhereForSelect
+	}
+
+	where := strings.TrimPrefix(rec.Data.WhereForSelect, "WHERE ")
+
+	return fmt.Sprintf("WHERE %s AND (%s)", rec.Data.JoinOn, where)
+}
+
+func (rec *QueryType) Exec(query string, valueList ...interface{}) (sql.Result, error) {
+	result, err := rec.ExecContext(context.Background(), query, valueList...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rec *QueryType) ExecContext(ctx context.Context, query string, valueList ...interface{}) (sql.Result, error) {
+	var err error
+	var result sql.Result
+
+	if rec.DB == nil && rec.TX == nil {
+		return nil, errors.New("database is null")
+	}
+
+	if rec.modeLog {
+		fmt.Printf("query: %v\n", query)
+		fmt.Printf("value: %v\n", valueList)
+	}
+
+	if rec.TX != nil {
+		result, err = rec.TX.ExecContext(ctx, query, valueList...)
+	} else {
+		result, err = rec.DB.ExecContext(ctx, query, valueList...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rec *QueryType) ExecQuery(dest interface{}, query string, valueList ...interface{}) error {
+	err := rec.ExecQueryContext(context.Background(), dest, query, valueList...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *QueryType) queryContext(ctx context.Context, query string, valueList ...interface{}) (*sql.Rows, error) {
+	var err error
+	var rows *sql.Rows
+
+	if rec.DB == nil && rec.TX == nil {
+		return nil, errors.New("database is null")
+	}
+
+	if rec.modeLog {
+		fmt.Printf("query: %v\n", query)
+		fmt.Printf("value: %v\n", valueList)
+	}
+
+	if rec.TX != nil {
+		rows, err = rec.TX.QueryContext(ctx, query, valueList...)
+	} else {
+		rows, err = rec.DB.QueryContext(ctx, query, valueList...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (rec *QueryType) ExecQueryContext(ctx context.Context, dest interface{}, query string, valueList ...interface{}) error {
+	var err error
+	var rows *sql.Rows
+
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return errors.New("dest is not pointer. Should be type *[]struct")
+	}
+	destDirect := destValue.Elem()
+
+	rows, err = rec.queryContext(ctx, query, valueList...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	columnList, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	// *[]struct, *[]map[string]interface{} and *[]scalar are all rows, the others are the first row
+	if destDirect.Kind() == reflect.Slice && !isScanType(destDirect.Type()) {
+		scan, err := rec.makeScanFunc(destDirect.Type().Elem(), columnList)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			val := reflect.New(destDirect.Type().Elem()).Elem()
+
+			err = scan(rows, val)
+			if err != nil {
+				return err
+			}
+
+			destDirect.Set(reflect.Append(destDirect, val))
+		}
+	} else {
+		scan, err := rec.makeScanFunc(destDirect.Type(), columnList)
+		if err != nil {
+			return err
+		}
+
+		if !rows.Next() {
+			err = rows.Err()
+			if err != nil {
+				return err
+			}
+
+			return ErrNotFound
+		}
+
+		err = scan(rows, destDirect)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isScanType is true if the type is scanned as one column like int, string, []byte, time.Time and sql.Scanner.
+func isScanType(base reflect.Type) bool {
+	if reflect.PtrTo(base).Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem()) {
+		return true
+	}
+
+	switch base.Kind() {
+	case reflect.Struct:
+		return base == reflect.TypeOf(time.Time{})
+	case reflect.Map, reflect.Array, reflect.Func, reflect.Chan:
+		return false
+	case reflect.Slice:
+		return base.Elem().Kind() == reflect.Uint8
+	}
+
+	return true
+}
+
+// makeScanFunc makes the function scanning a row into the value of base.
+func (rec *QueryType) makeScanFunc(base reflect.Type, columnList []string) (func(rows *sql.Rows, val reflect.Value) error, error) {
+	var err error
+
+	if isScanType(base) {
+		if len(columnList) != 1 {
+			return nil, errors.New("column length is not 1")
+		}
+
+		return func(rows *sql.Rows, val reflect.Value) error {
+			return rows.Scan(val.Addr().Interface())
+		}, nil
+	}
+
+	switch base.Kind() {
+	case reflect.Struct:
+		var tagIndexMap map[string][]int
+		{
+			tagIndexMap, err = makeTagIndexMap(base, structFieldTagNameColumn)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(tagIndexMap) != len(columnList) {
+				tagIndexMapFlag := true
+				for _, column := range columnList {
+					_, ok := tagIndexMap[column]
+					if !ok {
+						tagIndexMapFlag = true
+						break
+					}
+					tagIndexMapFlag = false
+				}
+				if tagIndexMapFlag {
+					baseValue := reflect.New(base)
+					val := reflect.Indirect(baseValue)
+					if val.NumField() != len(columnList) {
+						return nil, errors.New("length does not match")
+					}
+					for key, column := range columnList {
+						tagIndexMap[column] = []int{key}
+					}
+				}
+			}
+		}
+
+		scanList := make([]interface{}, len(columnList))
+
+		return func(rows *sql.Rows, val reflect.Value) error {
+			for key, column := range columnList {
+				indexList, ok := tagIndexMap[column]
+				if !ok {
+					return errors.New("column not exist")
+				}
+
+				field := val
+				for _, index := range indexList {
+					field = field.Field(index)
+				}
+
+				scanList[key] = field.Addr().Interface()
+			}
+
+			return rows.Scan(scanList...)
+		}, nil
+	case reflect.Map:
+		if base != reflect.TypeOf(map[string]interface{}{}) {
+			return nil, errors.New("type *[]struct or *[]map[string]interface{}")
+		}
+
+		columnChangeMap := make(map[string]string)
+		if rec.modeResultKey == resultKeyModeCamelCase {
+			for _, val := range columnList {
+				columnChangeMap[val] = toCamelCase(val)
+			}
+		} else if rec.modeResultKey == resultKeyModeSnakeCase {
+			for _, val := range columnList {
+				columnChangeMap[val] = toSnakeCase(val)
+			}
+		} else {
+			for _, val := range columnList {
+				columnChangeMap[val] = val
+			}
+		}
+
+		var valList = make([]interface{}, len(columnList))
+		var scanList = make([]interface{}, len(columnList))
+		for key := range columnList {
+			scanList[key] = &valList[key]
+		}
+
+		return func(rows *sql.Rows, val reflect.Value) error {
+			err := rows.Scan(scanList...)
+			if err != nil {
+				return err
+			}
+
+			scanMap := make(map[string]interface{})
+			for key, column := range columnList {
+				name := columnChangeMap[column]
+				scanMap[name] = valList[key]
+			}
+
+			val.Set(reflect.ValueOf(scanMap))
+
+			return nil
+		}, nil
+	}
+
+	return nil, errors.New("type *[]struct or *[]map[string]interface{}")
+}
+
+func (rec *QueryType) Select(dest interface{}) error {
+	err := rec.SelectContext(context.Background(), dest)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *QueryType) SelectContext(ctx context.Context, dest interface{}) error {
+	if rec.Lock != nil && rec.TX == nil {
+		return errors.New("lock needs transaction")
+	}
+
+	query, valueList, err := rec.GetSelectQuery()
+	if err != nil {
+		return err
+	}
+
+	err = rec.ExecQueryContext(ctx, dest, query, valueList...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *QueryType) SelectCount(dest interface{}) error {
+	err := rec.SelectCountContext(context.Background(), dest)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *QueryType) SelectCountContext(ctx context.Context, dest interface{}) error {
+	query, valueList, err := rec.GetSelectCountQuery()
+	if err != nil {
+		return err
+	}
+
+	err = rec.ExecQueryContext(ctx, dest, query, valueList...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *QueryType) ExecRows(query string, valueList ...interface{}) (*RowsType, error) {
+	rows, err := rec.ExecRowsContext(context.Background(), query, valueList...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// ExecRowsContext returns the cursor of the query. The cursor must be closed.
+func (rec *QueryType) ExecRowsContext(ctx context.Context, query string, valueList ...interface{}) (*RowsType, error) {
+	rows, err := rec.queryContext(ctx, query, valueList...)
+	if err != nil {
+		return nil, err
+	}
+
+	columnList, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+
+	return &RowsType{
+		query:      rec,
+		rows:       rows,
+		columnList: columnList,
+		scanMap:    make(map[reflect.Type]func(rows *sql.Rows, val reflect.Value) error),
+	}, nil
+}
+
+func (rec *QueryType) Rows() (*RowsType, error) {
+	rows, err := rec.RowsContext(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (rec *QueryType) RowsContext(ctx context.Context) (*RowsType, error) {
+	if rec.Lock != nil && rec.TX == nil {
+		return nil, errors.New("lock needs transaction")
+	}
+
+	query, valueList, err := rec.GetSelectQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := rec.ExecRowsContext(ctx, query, valueList...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (rec *QueryType) Each(dest interface{}, fn func() error) error {
+	err := rec.EachContext(context.Background(), dest, fn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EachContext scans each row into dest and calls fn. The loop stops if fn returns error.
+func (rec *QueryType) EachContext(ctx context.Context, dest interface{}, fn func() error) error {
+	rows, err := rec.RowsContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		err = rows.Scan(dest)
+		if err != nil {
+			return err
+		}
+
+		err = fn()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *QueryType) Insert() (sql.Result, error) {
+	result, err := rec.InsertContext(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rec *QueryType) InsertContext(ctx context.Context) (sql.Result, error) {
+	query, valueList, err := rec.GetInsertQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := rec.ExecContext(ctx, query, valueList...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rec *QueryType) Update() (sql.Result, error) {
+	result, err := rec.UpdateContext(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rec *QueryType) UpdateContext(ctx context.Context) (sql.Result, error) {
+	query, valueList, err := rec.GetUpdateQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := rec.ExecContext(ctx, query, valueList...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rec *QueryType) Delete() (sql.Result, error) {
+	result, err := rec.DeleteContext(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rec *QueryType) DeleteContext(ctx context.Context) (sql.Result, error) {
+	query, valueList, err := rec.GetDeleteQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := rec.ExecContext(ctx, query, valueList...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rec *QueryType) InsertReturning(dest interface{}) error {
+	err := rec.InsertReturningContext(context.Background(), dest)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// InsertReturningContext scans the SetReturning columns of the inserted rows into dest.
+// When the database does not support RETURNING, one row is inserted and selected with the SetLastInsertId column and LastInsertId.
+func (rec *QueryType) InsertReturningContext(ctx context.Context, dest interface{}) error {
+	if len(rec.ReturningList) < 1 {
+		return errors.New("returning not exist")
+	}
+
+	dialect, err := rec.getDialect()
+	if err != nil {
+		return err
+	}
+
+	if !dialect.Support(DialectFeatureReturning) {
+		err = rec.insertReturningLastInsertId(ctx, dest)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	query, valueList, err := rec.GetInsertQuery()
+	if err != nil {
+		return err
+	}
+
+	err = rec.ExecQueryContext(ctx, dest, query, valueList...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *QueryType) insertReturningLastInsertId(ctx context.Context, dest interface{}) error {
+	if len(rec.ValuesList) != 1 {
+		return errors.New("returning with LastInsertId needs one values")
+	}
 
-		str := rec.Data.Where
-		if str == "" {
-			return "", nil, errors.New("where not exist")
-		}
-		query = fmt.Sprintf("%s %s", query, str)
+	if isNil(rec.LastInsertIdPtr) {
+		return errors.New("returning with LastInsertId needs SetLastInsertId")
 	}
 
-	valueList := rec.Data.ValueList
+	switch reflect.Indirect(reflect.ValueOf(rec.LastInsertIdPtr)).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return errors.New("last insert id column is not integer")
+	}
 
-	return query, valueList, nil
-}
+	insertQuery := *rec
+	insertQuery.ReturningList = nil
 
-func (rec *QueryType) GetDeleteQuery() (string, []interface{}, error) {
-	var err error
-	query := ""
+	result, err := insertQuery.InsertContext(ctx)
+	if err != nil {
+		return err
+	}
 
-	err = rec.buildMeta()
+	id, err := result.LastInsertId()
 	if err != nil {
-		return "", nil, err
+		return err
 	}
 
-	query = "DELETE FROM"
+	selectQuery := rec.newQuery()
+	selectQuery.SetTableAs(rec.Table.TablePtr, rec.Table.TableAs)
+	for _, returningData := range rec.ReturningList {
+		selectQuery.SetSelect(returningData.ColumnPtr)
+	}
+	selectQuery.SetWhereIs(rec.LastInsertIdPtr, id)
 
-	{
-		err = rec.buildTable()
-		if err != nil {
-			return "", nil, err
-		}
+	err = selectQuery.SelectContext(ctx, dest)
+	if err != nil {
+		return err
+	}
 
-		str := rec.Data.Table
-		if str == "" {
-			return "", nil, errors.New("table not exist")
-		}
-		query = fmt.Sprintf("%s %s", query, str)
+	return nil
+}
+
+func (rec *QueryType) UpdateReturning(dest interface{}) error {
+	err := rec.UpdateReturningContext(context.Background(), dest)
+	if err != nil {
+		return err
 	}
 
-	{
-		err = rec.buildWhere()
-		if err != nil {
-			return "", nil, err
-		}
+	return nil
+}
 
-		str := rec.Data.Where
-		if str == "" {
-			return "", nil, errors.New("where not exist")
-		}
-		query = fmt.Sprintf("%s %s", query, str)
+func (rec *QueryType) UpdateReturningContext(ctx context.Context, dest interface{}) error {
+	if len(rec.ReturningList) < 1 {
+		return errors.New("returning not exist")
 	}
 
-	valueList := rec.Data.ValueList
+	query, valueList, err := rec.GetUpdateQuery()
+	if err != nil {
+		return err
+	}
 
-	return query, valueList, nil
-}
+	err = rec.ExecQueryContext(ctx, dest, query, valueList...)
+	if err != nil {
+		return err
+	}
 
-func (rec *QueryType) Exec(query string, valueList ...interface{}) (sql.Result, error) {
-	var err error
-	var result sql.Result
+	return nil
+}
 
-	if rec.DB == nil && rec.TX == nil {
-		return nil, errors.New("database is null")
+func (rec *QueryType) DeleteReturning(dest interface{}) error {
+	err := rec.DeleteReturningContext(context.Background(), dest)
+	if err != nil {
+		return err
 	}
 
-	if rec.modeLog {
-		fmt.Printf("query: %v\n", query)
-		fmt.Printf("value: %v\n", valueList)
+	return nil
+}
+
+func (rec *QueryType) DeleteReturningContext(ctx context.Context, dest interface{}) error {
+	if len(rec.ReturningList) < 1 {
+		return errors.New("returning not exist")
 	}
 
-	if rec.TX != nil {
-		result, err = rec.TX.Exec(query, valueList...)
-	} else {
-		result, err = rec.DB.Exec(query, valueList...)
+	query, valueList, err := rec.GetDeleteQuery()
+	if err != nil {
+		return err
 	}
+
+	err = rec.ExecQueryContext(ctx, dest, query, valueList...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return result, nil
+	return nil
 }
 
-func (rec *QueryType) ExecQuery(dest interface{}, query string, valueList ...interface{}) error {
-	var err error
-	var rows *sql.Rows
+// newAggregateQuery copies the table, join and where of the query without the select and order.
+// distinct and limit change the rows, so they are an error instead of being ignored.
+func (rec *QueryType) newAggregateQuery() (*QueryType, error) {
+	if len(rec.UnionList) > 0 {
+		return nil, errors.New("aggregate with union not supported")
+	}
 
-	if rec.DB == nil && rec.TX == nil {
-		return errors.New("database is null")
+	if len(rec.GroupByList) > 0 {
+		return nil, errors.New("aggregate with group by not supported")
 	}
 
-	if rec.modeLog {
-		fmt.Printf("query: %v\n", query)
-		fmt.Printf("value: %v\n", valueList)
+	if rec.Distinct != nil {
+		return nil, errors.New("aggregate with distinct not supported")
 	}
 
-	if rec.TX != nil {
-		rows, err = rec.TX.Query(query, valueList...)
-	} else {
-		rows, err = rec.DB.Query(query, valueList...)
+	if rec.Limit > 0 || rec.Offset > 0 {
+		return nil, errors.New("aggregate with limit not supported")
 	}
+
+	queryData := *rec
+	queryData.SelectList = nil
+	queryData.WindowList = nil
+	queryData.OrderByList = nil
+	queryData.Lock = nil
+	queryData.Data = nil
+
+	return &queryData, nil
+}
+
+// selectValueContext runs the query of one row and scans the row into destList.
+func (rec *QueryType) selectValueContext(ctx context.Context, query string, valueList []interface{}, destList ...interface{}) error {
+	rows, err := rec.queryContext(ctx, query, valueList...)
 	if err != nil {
 		return err
 	}
@@ -2205,143 +4527,114 @@ func (rec *QueryType) ExecQuery(dest interface{}, query string, valueList ...int
 		_ = rows.Close()
 	}()
 
-	columnList, err := rows.Columns()
+	if !rows.Next() {
+		err = rows.Err()
+		if err != nil {
+			return err
+		}
+
+		return ErrNotFound
+	}
+
+	err = rows.Scan(destList...)
 	if err != nil {
 		return err
 	}
 
-	destValue := reflect.ValueOf(dest)
-	destValueType := destValue.Type().String()
-	{
-		elem := destValue
-		if elem.Kind() != reflect.Ptr {
-			return errors.New("dest is not pointer. Should be type *[]struct")
-		}
-		elem = destValue.Elem()
-		if elem.Kind() != reflect.Slice {
-			return errors.New("dest is not pointer slice. Should be type *[]struct or *[]map[string]interface {}")
-		}
+	return nil
+}
+
+func (rec *QueryType) aggregateContext(ctx context.Context, format string, columnPtr interface{}, dest interface{}) error {
+	queryData, err := rec.newAggregateQuery()
+	if err != nil {
+		return err
 	}
-	destDirect := reflect.Indirect(destValue)
 
-	destType := destValue.Type()
-	if destType.Kind() == reflect.Ptr {
-		destType = destType.Elem()
+	queryData.SetSelectFormat(format, columnPtr)
+	query, valueList, err := queryData.GetSelectQuery()
+	if err != nil {
+		return err
 	}
 
-	base := destType.Elem()
+	err = rec.selectValueContext(ctx, query, valueList, dest)
+	if err != nil {
+		return err
+	}
 
-	switch base.Kind() {
-	case reflect.Struct:
-		var tagIndexMap map[string][]int
-		{
-			tagIndexMap, err = makeTagIndexMap(base, structFieldTagNameColumn)
-			if err != nil {
-				return err
-			}
+	return nil
+}
 
-			if len(tagIndexMap) != len(columnList) {
-				tagIndexMapFlag := true
-				for _, column := range columnList {
-					 _, ok := tagIndexMap[column]
-					 if !ok {
-						  tagIndexMapFlag = true
-						  break
-					 }
-					 tagIndexMapFlag = false
-				}
-				if tagIndexMapFlag {
-					 baseValue := reflect.New(base)
-					 val := reflect.Indirect(baseValue)
-					 if val.NumField() != len(columnList) {
-						  return errors.New("length does not match")
-					 }
-					 for key, column := range columnList {
-						  tagIndexMap[column] = []int{key}
-					 }
-				}
-			}
-		}
+func (rec *QueryType) Exists() (bool, error) {
+	exists, err := rec.ExistsContext(context.Background())
+	if err != nil {
+		return false, err
+	}
 
-		scanList := make([]interface{}, len(columnList))
-		for rows.Next() {
-			baseValue := reflect.New(base)
-			val := reflect.Indirect(baseValue)
+	return exists, nil
+}
 
-			for key, column := range columnList {
-				indexList, ok := tagIndexMap[column]
-				if !ok {
-					return errors.New("column not exist")
-				}
+func (rec *QueryType) ExistsContext(ctx context.Context) (bool, error) {
+	queryData, err := rec.newAggregateQuery()
+	if err != nil {
+		return false, err
+	}
 
-				field := val
-				for _, index := range indexList {
-					field = field.Field(index)
-				}
+	queryData.SetSelectString("1")
+	query, valueList, err := queryData.GetSelectQuery()
+	if err != nil {
+		return false, err
+	}
 
-				scanList[key] = field.Addr().Interface()
-			}
+	var exists bool
+	err = rec.selectValueContext(ctx, fmt.Sprintf("SELECT EXISTS (%s)", query), valueList, &exists)
+	if err != nil {
+		return false, err
+	}
 
-			err = rows.Scan(scanList...)
-			if err != nil {
-				return err
-			}
+	return exists, nil
+}
 
-			destDirect.Set(reflect.Append(destDirect, val))
-		}
-	default:
-		if destValueType == "*[]map[string]interface {}" {
-			columnChangeMap := make(map[string]string)
-			if rec.modeResultKey == resultKeyModeCamelCase {
-				for _, val := range columnList {
-					columnChangeMap[val] = toCamelCase(val)
-				}
-			} else if rec.modeResultKey == resultKeyModeSnakeCase {
-				for _, val := range columnList {
-					columnChangeMap[val] = toSnakeCase(val)
-				}
-			} else {
-				for _, val := range columnList {
-					columnChangeMap[val] = val
-				}
-			}
+// Sum scans the sum of columnPtr into dest. It is 0 if no row.
+func (rec *QueryType) Sum(columnPtr interface{}, dest interface{}) error {
+	err := rec.SumContext(context.Background(), columnPtr, dest)
+	if err != nil {
+		return err
+	}
 
-			var valList = make([]interface{}, len(columnList))
-			var scanList = make([]interface{}, len(columnList))
-			for key, _ := range columnList {
-				scanList[key] = &valList[key]
-			}
+	return nil
+}
 
-			for rows.Next() {
-				err = rows.Scan(scanList...)
-				if err != nil {
-					return err
-				}
+func (rec *QueryType) SumContext(ctx context.Context, columnPtr interface{}, dest interface{}) error {
+	err := rec.aggregateContext(ctx, "coalesce(sum(%s), 0)", columnPtr, dest)
+	if err != nil {
+		return err
+	}
 
-				scanMap := make(map[string]interface{})
-				for key, column := range columnList {
-					name := columnChangeMap[column]
-					scanMap[name] = valList[key]
-				}
+	return nil
+}
 
-				scanMapType := reflect.ValueOf(scanMap)
-				destDirect.Set(reflect.Append(destDirect, scanMapType))
-			}
-		} else {
-			return errors.New("type *[]struct or *[]map[string]interface{}")
-		}
+// Max scans the max of columnPtr into dest. It is NULL if no row, so use the Null types for dest.
+func (rec *QueryType) Max(columnPtr interface{}, dest interface{}) error {
+	err := rec.MaxContext(context.Background(), columnPtr, dest)
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func (rec *QueryType) Select(dest interface{}) error {
-	query, valueList, err := rec.GetSelectQuery()
+func (rec *QueryType) MaxContext(ctx context.Context, columnPtr interface{}, dest interface{}) error {
+	err := rec.aggregateContext(ctx, "max(%s)", columnPtr, dest)
 	if err != nil {
 		return err
 	}
 
-	err = rec.ExecQuery(dest, query, valueList...)
+	return nil
+}
+
+// Min scans the min of columnPtr into dest. It is NULL if no row, so use the Null types for dest.
+func (rec *QueryType) Min(columnPtr interface{}, dest interface{}) error {
+	err := rec.MinContext(context.Background(), columnPtr, dest)
 	if err != nil {
 		return err
 	}
@@ -2349,13 +4642,18 @@ func (rec *QueryType) Select(dest interface{}) error {
 	return nil
 }
 
-func (rec *QueryType) SelectCount(dest interface{}) error {
-	query, valueList, err := rec.GetSelectCountQuery()
+func (rec *QueryType) MinContext(ctx context.Context, columnPtr interface{}, dest interface{}) error {
+	err := rec.aggregateContext(ctx, "min(%s)", columnPtr, dest)
 	if err != nil {
 		return err
 	}
 
-	err = rec.ExecQuery(dest, query, valueList...)
+	return nil
+}
+
+// Avg scans the average of columnPtr into dest. It is NULL if no row, so use the Null types for dest.
+func (rec *QueryType) Avg(columnPtr interface{}, dest interface{}) error {
+	err := rec.AvgContext(context.Background(), columnPtr, dest)
 	if err != nil {
 		return err
 	}
@@ -2363,44 +4661,93 @@ func (rec *QueryType) SelectCount(dest interface{}) error {
 	return nil
 }
 
-func (rec *QueryType) Insert() (sql.Result, error) {
-	query, valueList, err := rec.GetInsertQuery()
+func (rec *QueryType) AvgContext(ctx context.Context, columnPtr interface{}, dest interface{}) error {
+	err := rec.aggregateContext(ctx, "avg(%s)", columnPtr, dest)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	result, err := rec.Exec(query, valueList...)
+	return nil
+}
+
+func (rec *QueryType) CountDistinct(columnPtr interface{}) (int, error) {
+	count, err := rec.CountDistinctContext(context.Background(), columnPtr)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return result, nil
+	return count, nil
 }
 
-func (rec *QueryType) Update() (sql.Result, error) {
-	query, valueList, err := rec.GetUpdateQuery()
+func (rec *QueryType) CountDistinctContext(ctx context.Context, columnPtr interface{}) (int, error) {
+	var count int
+	err := rec.aggregateContext(ctx, "count(DISTINCT %s)", columnPtr, &count)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	result, err := rec.Exec(query, valueList...)
+	return count, nil
+}
+
+// Pluck scans columnPtr of each row into dest. dest is a pointer of a slice like *[]int.
+func (rec *QueryType) Pluck(columnPtr interface{}, dest interface{}) error {
+	err := rec.PluckContext(context.Background(), columnPtr, dest)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return result, nil
+	return nil
 }
 
-func (rec *QueryType) Delete() (sql.Result, error) {
-	query, valueList, err := rec.GetDeleteQuery()
+func (rec *QueryType) PluckContext(ctx context.Context, columnPtr interface{}, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return errors.New("dest is not pointer slice")
+	}
+	destDirect := destValue.Elem()
+	base := destDirect.Type().Elem()
+
+	if len(rec.UnionList) > 0 {
+		return errors.New("pluck with union not supported")
+	}
+
+	if rec.Lock != nil && rec.TX == nil {
+		return errors.New("lock needs transaction")
+	}
+
+	queryData := *rec
+	queryData.SelectList = nil
+	queryData.WindowList = nil
+	queryData.Data = nil
+	queryData.SetSelect(columnPtr)
+	query, valueList, err := queryData.GetSelectQuery()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	result, err := rec.Exec(query, valueList...)
+	rows, err := rec.queryContext(ctx, query, valueList...)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer func() {
+		_ = rows.Close()
+	}()
 
-	return result, nil
+	for rows.Next() {
+		val := reflect.New(base)
+
+		err = rows.Scan(val.Interface())
+		if err != nil {
+			return err
+		}
+
+		destDirect.Set(reflect.Append(destDirect, val.Elem()))
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
@@ -163,6 +163,935 @@ func TestQueryType_GetSelectCountQuery(t *testing.T) {
 	})
 }
 
+func TestQueryType_SubQuery(t *testing.T) {
+	t.Run("success where in", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		subQuery := QueryType{}
+		subQuery.SetTable(&testUserTable)
+		subQuery.SetSelect(&testUserTable.Id)
+		subQuery.SetWhereIs(&testUserTable.Name, "user")
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetWhereIs(&testItemTable.Name, 1)
+		query.SetWhereIn(&testItemTable.UserId, &subQuery)
+		query.SetWhereIsNot(&testItemTable.Id, 2)
+		str, valueList, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "test_item"."id" FROM "test_item" WHERE "test_item"."name" = $1 AND "test_item"."user_id" IN (SELECT "test_user"."id" FROM "test_user" WHERE "test_user"."name" = $2) AND "test_item"."id" != $3`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", valueList)
+
+			check := fmt.Sprintf("%v", []interface{}{1, "user", 2})
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success reuse", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		subQuery := QueryType{}
+		subQuery.SetTable(&testUserTable)
+		subQuery.SetSelect(&testUserTable.Id)
+		subQuery.SetWhereIs(&testUserTable.Name, "user")
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetWhereIs(&testItemTable.Name, 1)
+		query.SetWhereIn(&testItemTable.UserId, &subQuery)
+		_, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		str, valueList, err := subQuery.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := fmt.Sprintf("%v %v", str, valueList)
+
+			check := `SELECT "test_user"."id" FROM "test_user" WHERE "test_user"."name" = $1 [user]`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success select and exists", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		selectQuery := QueryType{}
+		selectQuery.SetTableAs(&testUserTable, "u")
+		selectQuery.SetSelect(&testUserTable.Name)
+		selectQuery.SetWhereFormat("%s = \"test_item\".\"user_id\"", &testUserTable.Id)
+
+		existsQuery := QueryType{}
+		existsQuery.SetTable(&testUserTable)
+		existsQuery.SetSelectString("1")
+		existsQuery.SetWhereIsNull(&testItemTable.DeletedAt)
+		existsQuery.SetWhereIs(&testUserTable.Pass, "pass")
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetSelectQueryAs(&selectQuery, "user_name")
+		query.SetWhereIs(&testItemTable.Name, 1)
+		query.SetWhereExists(&existsQuery)
+		str, valueList, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "test_item"."id", (SELECT "u"."name" FROM "test_user" as "u" WHERE "u"."id" = "test_item"."user_id") as "user_name" FROM "test_item" WHERE "test_item"."name" = $1 AND EXISTS (SELECT 1 FROM "test_user" WHERE "test_item"."deleted_at" IS NULL AND "test_user"."pass" = $2)`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", valueList)
+
+			check := fmt.Sprintf("%v", []interface{}{1, "pass"})
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error subquery select not exist", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		subQuery := QueryType{}
+		subQuery.SetTable(&testUserTable)
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetWhereIn(&testItemTable.UserId, &subQuery)
+		_, _, err := query.GetSelectQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `select not exist`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+type TestItemPath struct {
+	Id    int `column:"id" json:"id"`
+	Depth int `column:"depth" json:"depth"`
+}
+
+func TestQueryType_With(t *testing.T) {
+	t.Run("success recursive", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testItemPathTable := TestItemPath{}
+
+		baseQuery := QueryType{}
+		baseQuery.SetTable(&testItemTable)
+		baseQuery.SetSelect(&testItemTable.Id)
+		baseQuery.SetSelectStringAs("1", "depth")
+		baseQuery.SetWhereIs(&testItemTable.Id, 1)
+
+		recursiveQuery := QueryType{}
+		recursiveQuery.SetTable(&testItemTable)
+		recursiveQuery.SetJoin(&testItemPathTable, &testItemPathTable.Id, &testItemTable.UserId)
+		recursiveQuery.SetSelect(&testItemTable.Id)
+		recursiveQuery.SetSelectFormat("%s + 1", &testItemPathTable.Depth)
+		recursiveQuery.SetWhereLt(&testItemPathTable.Depth, 5)
+
+		query := QueryType{}
+		query.SetWithRecursive(&testItemPathTable, &baseQuery, &recursiveQuery)
+		query.SetTable(&testItemPathTable)
+		query.SetSelectAll(&testItemPathTable)
+		query.SetWhereGt(&testItemPathTable.Depth, 1)
+		str, valueList, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `WITH RECURSIVE "test_item_path" AS (SELECT "test_item"."id", 1 as "depth" FROM "test_item" WHERE "test_item"."id" = $1 UNION ALL SELECT "test_item"."id", "test_item_path"."depth" + 1 FROM "test_item" INNER JOIN "test_item_path" ON "test_item_path"."id" = "test_item"."user_id" WHERE "test_item_path"."depth" < $2) SELECT "test_item_path".* FROM "test_item_path" WHERE "test_item_path"."depth" > $3`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", valueList)
+
+			check := fmt.Sprintf("%v", []interface{}{1, 5, 1})
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error with query not exist", func(t *testing.T) {
+		testItemPathTable := TestItemPath{}
+
+		query := QueryType{}
+		query.SetWith(&testItemPathTable, nil)
+		query.SetTable(&testItemPathTable)
+		query.SetSelectAll(&testItemPathTable)
+		_, _, err := query.GetSelectQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `with query not exist`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_Union(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		unionQuery := QueryType{}
+		unionQuery.SetTable(&testUserTable)
+		unionQuery.SetSelect(&testUserTable.Id)
+		unionQuery.SetWhereIs(&testUserTable.Name, "user")
+		unionQuery.SetOrderByDesc(&testUserTable.Id)
+		unionQuery.SetLimit(1)
+
+		exceptQuery := QueryType{}
+		exceptQuery.SetTable(&testUserTable)
+		exceptQuery.SetSelect(&testUserTable.Id)
+		exceptQuery.SetWhereIs(&testUserTable.Pass, "pass")
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetWhereIs(&testItemTable.Name, 1)
+		query.SetUnionAll(&unionQuery)
+		query.SetExcept(&exceptQuery)
+		query.SetOrderBy(&testItemTable.Id)
+		query.SetLimit(10)
+		str, valueList, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "test_item"."id" FROM "test_item" WHERE "test_item"."name" = $1 UNION ALL (SELECT "test_user"."id" FROM "test_user" WHERE "test_user"."name" = $2 ORDER BY "test_user"."id" DESC LIMIT 1) EXCEPT SELECT "test_user"."id" FROM "test_user" WHERE "test_user"."pass" = $3 ORDER BY "id" LIMIT 10`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", valueList)
+
+			check := fmt.Sprintf("%v", []interface{}{1, "user", "pass"})
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success count", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		unionQuery := QueryType{}
+		unionQuery.SetTable(&testUserTable)
+		unionQuery.SetSelect(&testUserTable.Id)
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetUnion(&unionQuery)
+		str, _, err := query.GetSelectCountQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT count(*) FROM (SELECT "test_item"."id" FROM "test_item" UNION SELECT "test_user"."id" FROM "test_user") as "count_result"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success count limit", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		unionQuery := QueryType{}
+		unionQuery.SetTable(&testUserTable)
+		unionQuery.SetSelect(&testUserTable.Id)
+		unionQuery.SetOrderBy(&testUserTable.Id)
+		unionQuery.SetLimit(1)
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetUnion(&unionQuery)
+		query.SetOrderBy(&testItemTable.Id)
+		query.SetLimit(10)
+		query.SetOffset(20)
+		str, _, err := query.GetSelectCountQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT count(*) FROM (SELECT "test_item"."id" FROM "test_item" UNION (SELECT "test_user"."id" FROM "test_user" ORDER BY "test_user"."id" LIMIT 1)) as "count_result"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success sqlite query limit", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		unionQuery := QueryType{}
+		unionQuery.SetTable(&testUserTable)
+		unionQuery.SetSelect(&testUserTable.Id)
+		unionQuery.SetOrderBy(&testUserTable.Id)
+		unionQuery.SetLimit(1)
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeSqlite)
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetUnion(&unionQuery)
+		str, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "test_item"."id" FROM "test_item" UNION SELECT * FROM (SELECT "test_user"."id" FROM "test_user" ORDER BY "test_user"."id" LIMIT 1)`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_Lock(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetJoinAs(&testUserTable, "u", &testUserTable.Id, &testItemTable.UserId)
+		query.SetSelect(&testItemTable.Id)
+		query.SetWhereIsNull(&testItemTable.DeletedAt)
+		query.SetOrderBy(&testItemTable.Id)
+		query.SetLimit(1)
+		query.SetForNoKeyUpdate()
+		query.SetLockOf(&testItemTable)
+		query.SetLockSkipLocked()
+		str, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "test_item"."id" FROM "test_item" INNER JOIN "test_user" as "u" ON "u"."id" = "test_item"."user_id" WHERE "test_item"."deleted_at" IS NULL ORDER BY "test_item"."id" LIMIT 1 FOR NO KEY UPDATE OF "test_item" SKIP LOCKED`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success mysql", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetForShare()
+		query.SetLockNowait()
+		str, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT test_item.id FROM test_item FOR SHARE NOWAIT`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error mysql no key update not supported", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetForNoKeyUpdate()
+		_, _, err := query.GetSelectQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `lock no key update not supported`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error sqlite not supported", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeSqlite)
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetForUpdate()
+		_, _, err := query.GetSelectQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `lock not supported`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error transaction not exist", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		var resultList []TestItem
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetForUpdate()
+		err := query.Select(&resultList)
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `lock needs transaction`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_Distinct(t *testing.T) {
+	t.Run("success distinct on", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetDistinctOn(&testItemTable.UserId)
+		query.SetSelect(&testItemTable.UserId, &testItemTable.Name)
+		query.SetOrderBy(&testItemTable.UserId)
+		query.SetOrderByDesc(&testItemTable.CreatedAt)
+		str, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT DISTINCT ON ("test_item"."user_id") "test_item"."user_id", "test_item"."name" FROM "test_item" ORDER BY "test_item"."user_id", "test_item"."created_at" DESC`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success count one column", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetDistinct()
+		query.SetSelect(&testItemTable.UserId)
+		query.SetWhereIs(&testItemTable.Name, 1)
+		str, _, err := query.GetSelectCountQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT count(DISTINCT "test_item"."user_id") FROM "test_item" WHERE "test_item"."name" = $1`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success count columns", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetDistinct()
+		query.SetSelect(&testItemTable.UserId, &testItemTable.Name)
+		str, _, err := query.GetSelectCountQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT count(*) FROM (SELECT DISTINCT "test_item"."user_id", "test_item"."name" FROM "test_item") as "count_result"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success count limit offset", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetDistinct()
+		query.SetSelect(&testItemTable.UserId, &testItemTable.Name)
+		query.SetOrderBy(&testItemTable.UserId)
+		query.SetLimit(10)
+		query.SetOffset(20)
+		query.SetForUpdate()
+		str, _, err := query.GetSelectCountQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT count(*) FROM (SELECT DISTINCT "test_item"."user_id", "test_item"."name" FROM "test_item") as "count_result"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		str, _, err = query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT DISTINCT "test_item"."user_id", "test_item"."name" FROM "test_item" ORDER BY "test_item"."user_id" LIMIT 10 OFFSET 20 FOR UPDATE`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error distinct on order by", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetDistinctOn(&testItemTable.UserId)
+		query.SetSelect(&testItemTable.UserId, &testItemTable.Name)
+		query.SetOrderByDesc(&testItemTable.CreatedAt)
+		_, _, err := query.GetSelectQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `distinct on is different from the first order by`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error distinct on not supported", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetDistinctOn(&testItemTable.UserId)
+		query.SetSelect(&testItemTable.UserId)
+		_, _, err := query.GetSelectQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `distinct on not supported`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+type TestItemCount struct {
+	UserId int `column:"user_id" json:"userId"`
+	Count  int `column:"count" json:"count"`
+}
+
+type TestItemNameCount struct {
+	UserId int `column:"user_id" json:"userId"`
+	Count  int `column:"count" json:"count"`
+}
+
+func TestQueryType_Join(t *testing.T) {
+	t.Run("success full cross and composite", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+		testUserCrossTable := TestUser{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetJoinFull(&testUserTable, &testUserTable.Id, &testItemTable.UserId)
+		query.SetJoinOn(&testUserTable, &testUserTable.CreatedBy, &testItemTable.CreatedBy)
+		query.SetJoinCrossAs(&testUserCrossTable, "c")
+		query.SetSelect(&testItemTable.Id, &testUserTable.Id, &testUserCrossTable.Id)
+		str, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "test_item"."id", "test_user"."id", "c"."id" FROM "test_item" FULL OUTER JOIN "test_user" ON "test_user"."id" = "test_item"."user_id" AND "test_user"."created_by" = "test_item"."created_by" CROSS JOIN "test_user" as "c"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success query and lateral", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+		testItemCountTable := TestItemCount{}
+		testItemLastTable := TestItem{}
+
+		countQuery := QueryType{}
+		countQuery.SetTable(&testItemTable)
+		countQuery.SetSelect(&testItemTable.UserId)
+		countQuery.SetSelectStringAs("count(*)", "count")
+		countQuery.SetWhereIsNull(&testItemTable.DeletedAt)
+		countQuery.SetGroupBy(&testItemTable.UserId)
+
+		lastQuery := QueryType{}
+		lastQuery.SetTableAs(&testItemLastTable, "l")
+		lastQuery.SetSelectAll(&testItemLastTable)
+		lastQuery.SetWhereFormat("%s = \"test_user\".\"id\"", &testItemLastTable.UserId)
+		lastQuery.SetWhereGt(&testItemLastTable.Name, 1)
+		lastQuery.SetOrderByDesc(&testItemLastTable.Id)
+		lastQuery.SetLimit(1)
+
+		query := QueryType{}
+		query.SetTable(&testUserTable)
+		query.SetJoinLeftQuery(&testItemCountTable, &countQuery, &testItemCountTable.UserId, &testUserTable.Id)
+		query.SetJoinLeftLateral(&testItemLastTable, &lastQuery)
+		query.SetSelect(&testUserTable.Id, &testItemCountTable.Count, &testItemLastTable.Name)
+		query.SetWhereIs(&testUserTable.Name, "user")
+		str, valueList, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "test_user"."id", "test_item_count"."count", "test_item"."name" FROM "test_user" LEFT JOIN (SELECT "test_item"."user_id", count(*) as "count" FROM "test_item" WHERE "test_item"."deleted_at" IS NULL GROUP BY "user_id") as "test_item_count" ON "test_item_count"."user_id" = "test_user"."id" LEFT JOIN LATERAL (SELECT "l".* FROM "test_item" as "l" WHERE "l"."user_id" = "test_user"."id" AND "l"."name" > $1 ORDER BY "l"."id" DESC LIMIT 1) as "test_item" ON true WHERE "test_user"."name" = $2`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", valueList)
+
+			check := fmt.Sprintf("%v", []interface{}{1, "user"})
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error full join not supported", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetJoinFull(&testUserTable, &testUserTable.Id, &testItemTable.UserId)
+		query.SetSelect(&testItemTable.Id)
+		_, _, err := query.GetSelectQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `full join not supported`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_Window(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		window := Window()
+		window.SetPartitionBy(&testItemTable.UserId)
+		window.SetOrderByDesc(&testItemTable.CreatedAt)
+
+		windowTotal := Window()
+		windowTotal.SetPartitionBy(&testItemTable.UserId)
+		windowTotal.SetOrderBy(&testItemTable.Id)
+
+		windowFrame := WindowName("total")
+		windowFrame.SetFrame("ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW")
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSelect(&testItemTable.Id)
+		query.SetSelectOverAs("row_number()", nil, window, "row_number")
+		query.SetSelectOverAs("sum(%s)", &testItemTable.Name, windowFrame, "total")
+		query.SetSelectOver("count(*)", nil, WindowName("total"))
+		query.SetWindow("total", windowTotal)
+		query.SetWhereIs(&testItemTable.Name, 1)
+		query.SetOrderBy(&testItemTable.Id)
+		str, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "test_item"."id", row_number() OVER (PARTITION BY "test_item"."user_id" ORDER BY "test_item"."created_at" DESC) as "row_number", sum("test_item"."name") OVER ("total" ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) as "total", count(*) OVER "total" FROM "test_item" WHERE "test_item"."name" = $1 WINDOW "total" AS (PARTITION BY "test_item"."user_id" ORDER BY "test_item"."id") ORDER BY "test_item"."id"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success mysql", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		window := Window()
+		window.SetPartitionBy(&testItemTable.UserId)
+		window.SetOrderByDesc(&testItemTable.CreatedAt)
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetSelectOver("rank()", nil, window)
+		str, _, err := query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT rank() OVER (PARTITION BY test_item.user_id ORDER BY test_item.created_at DESC) FROM test_item`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error window meta not exist", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		window := Window()
+		window.SetPartitionBy(&testUserTable.Id)
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSelectOver("row_number()", nil, window)
+		_, _, err := query.GetSelectQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `window partition by meta not exist`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
 func TestQueryType_GetInsertQuery(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		item := TestItem{}
@@ -191,7 +1120,336 @@ func TestQueryType_GetInsertQuery(t *testing.T) {
 			&item.UserId,
 		)
 
-		str, valueList, err := query.GetInsertQuery()
+		str, valueList, err := query.GetInsertQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `INSERT INTO "test_item" ("created_at", "created_by", "updated_at", "updated_by", "deleted_at", "deleted_by", "name", "user_id") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", valueList)
+
+			var checkList []interface{}
+			checkList = append(checkList, item.CreatedAt)
+			checkList = append(checkList, item.CreatedBy)
+			checkList = append(checkList, item.UpdatedAt)
+			checkList = append(checkList, item.UpdatedBy)
+			checkList = append(checkList, item.DeletedAt)
+			checkList = append(checkList, item.DeletedBy)
+			checkList = append(checkList, item.Name)
+			checkList = append(checkList, item.UserId)
+			check := fmt.Sprintf("%v", checkList)
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success returning", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.Name, &testItemTable.UserId)
+		query.SetValues(1, 2)
+		query.SetReturning(&testItemTable.Id, &testItemTable.CreatedAt)
+		str, _, err := query.GetInsertQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `INSERT INTO "test_item" ("name", "user_id") VALUES ($1, $2) RETURNING "id", "created_at"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success conflict update", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.Id, &testItemTable.Name, &testItemTable.UserId)
+		query.SetValues(1, "name", 2)
+		query.SetConflict(&testItemTable.Id)
+		query.SetConflictUpdate(&testItemTable.Name, &testItemTable.UserId)
+		query.SetConflictWhereIsNull(&testItemTable.DeletedAt)
+		query.SetReturning(&testItemTable.Id)
+		str, _, err := query.GetInsertQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `INSERT INTO "test_item" ("id", "name", "user_id") VALUES ($1, $2, $3) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "user_id" = EXCLUDED."user_id" WHERE "test_item"."deleted_at" IS NULL RETURNING "id"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success conflict do nothing", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.Id, &testItemTable.Name)
+		query.SetValues(1, "name")
+		query.SetConflictDoNothing()
+		str, _, err := query.GetInsertQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `INSERT INTO "test_item" ("id", "name") VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success conflict mysql", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.Id, &testItemTable.Name, &testItemTable.UserId)
+		query.SetValues(1, "name", 2)
+		query.SetConflictUpdate(&testItemTable.Name, &testItemTable.UserId)
+		str, _, err := query.GetInsertQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `INSERT INTO test_item (id, name, user_id) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name), user_id = VALUES(user_id)`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error conflict column not exist", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.Id, &testItemTable.Name)
+		query.SetValues(1, "name")
+		query.SetConflictUpdate(&testItemTable.Name)
+		_, _, err := query.GetInsertQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `conflict column not exist`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error conflict update column not exist", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		for _, databaseType := range []string{DatabaseTypePostgresql, DatabaseTypeMysql} {
+			query := QueryType{}
+			query.Init(nil, nil, databaseType)
+			query.SetTable(&testItemTable)
+			query.SetValuesColumn(&testItemTable.Id, &testItemTable.Name)
+			query.SetValues(1, "name")
+			query.SetConflict(&testItemTable.Id)
+			query.SetConflictUpdate()
+			_, _, err := query.GetInsertQuery()
+			{
+				target := fmt.Sprintf("%v", err)
+
+				check := `conflict update column not exist`
+
+				if target != check {
+					t.Error("target:", target)
+					t.Error("check :", check)
+					return
+				}
+			}
+		}
+	})
+
+	t.Run("success values query", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		valuesQuery := QueryType{}
+		valuesQuery.SetTable(&testUserTable)
+		valuesQuery.SetSelect(&testUserTable.CreatedAt, &testUserTable.UpdatedAt)
+		valuesQuery.SetSelectString("1")
+		valuesQuery.SetSelect(&testUserTable.Id)
+		valuesQuery.SetWhereIs(&testUserTable.Name, "user")
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.CreatedAt, &testItemTable.UpdatedAt, &testItemTable.Name, &testItemTable.UserId)
+		query.SetValuesQuery(&valuesQuery)
+		query.SetReturning(&testItemTable.Id)
+		str, valueList, err := query.GetInsertQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `INSERT INTO "test_item" ("created_at", "updated_at", "name", "user_id") SELECT "test_user"."created_at", "test_user"."updated_at", 1, "test_user"."id" FROM "test_user" WHERE "test_user"."name" = $1 RETURNING "id"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", valueList)
+
+			check := fmt.Sprintf("%v", []interface{}{"user"})
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error values query column count", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testItemArchiveTable := TestItem{}
+
+		valuesQuery := QueryType{}
+		valuesQuery.SetTable(&testItemTable)
+		valuesQuery.SetSelectAll(&testItemTable)
+
+		query := QueryType{}
+		query.SetTable(&testItemArchiveTable)
+		query.SetValuesColumn(&testItemArchiveTable.Id, &testItemArchiveTable.Name)
+		query.SetValuesQuery(&valuesQuery)
+		_, _, err := query.GetInsertQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `values query is different from the number of valuescolumn`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error returning not supported", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetValuesColumn(&testItemTable.Name, &testItemTable.UserId)
+		query.SetValues(1, 2)
+		query.SetReturning(&testItemTable.Id)
+		_, _, err := query.GetInsertQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `returning not supported`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error table not exist", func(t *testing.T) {
+		query := QueryType{}
+		_, _, err := query.GetInsertQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `table not exist`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+}
+
+func TestQueryType_GetUpdateQuery(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		item := TestItem{}
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSet(&testItemTable.CreatedAt, item.CreatedAt)
+		query.SetSet(&testItemTable.CreatedBy, item.CreatedBy)
+		query.SetSet(&testItemTable.UpdatedAt, item.UpdatedAt)
+		query.SetSet(&testItemTable.UpdatedBy, item.UpdatedBy)
+		query.SetSet(&testItemTable.DeletedAt, item.DeletedAt)
+		query.SetSet(&testItemTable.DeletedBy, item.DeletedBy)
+		query.SetSet(&testItemTable.Name, item.Name)
+		query.SetSet(&testItemTable.UserId, item.UserId)
+		query.SetWhereString("1 = 1")
+		str, valueList, err := query.GetUpdateQuery()
 		if err != nil {
 			t.Error(err)
 			return
@@ -200,7 +1458,7 @@ func TestQueryType_GetInsertQuery(t *testing.T) {
 		{
 			target := str
 
-			check := `INSERT INTO "test_item" ("created_at", "created_by", "updated_at", "updated_by", "deleted_at", "deleted_by", "name", "user_id") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+			check := `UPDATE "test_item" SET "created_at" = $1, "created_by" = $2, "updated_at" = $3, "updated_by" = $4, "deleted_at" = $5, "deleted_by" = $6, "name" = $7, "user_id" = $8 WHERE 1 = 1`
 
 			if target != check {
 				t.Error("target:", target)
@@ -231,13 +1489,142 @@ func TestQueryType_GetInsertQuery(t *testing.T) {
 		}
 	})
 
-	t.Run("error table not exist", func(t *testing.T) {
+	t.Run("success expr", func(t *testing.T) {
+		testItemTable := TestItem{}
+
 		query := QueryType{}
-		_, _, err := query.GetInsertQuery()
+		query.SetTable(&testItemTable)
+		query.SetSet(&testItemTable.Name, Expr("%s - %s", Column(&testItemTable.Name), 1))
+		query.SetSet(&testItemTable.UserId, Expr("CASE WHEN %s > %s THEN %s ELSE %s END", Column(&testItemTable.Name), 10, Column(&testItemTable.UserId), nil))
+		query.SetSet(&testItemTable.UpdatedAt, Expr("NOW()"))
+		query.SetWhereIs(&testItemTable.Id, 2)
+		query.SetWhereGte(&testItemTable.Name, 1)
+		str, valueList, err := query.GetUpdateQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `UPDATE "test_item" SET "name" = "name" - $1, "user_id" = CASE WHEN "name" > $2 THEN "user_id" ELSE NULL END, "updated_at" = NOW() WHERE "id" = $3 AND "name" >= $4`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", valueList)
+
+			check := fmt.Sprintf("%v", []interface{}{1, 10, 2, 1})
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success expr table as", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTableAs(&testItemTable, "i")
+		query.SetSet(&testItemTable.Name, Expr("%s + %s", Column(&testItemTable.Name), 1))
+		query.SetWhereIs(&testItemTable.UserId, Column(&testItemTable.CreatedBy))
+		str, _, err := query.GetUpdateQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `UPDATE "test_item" SET "name" = "name" + $1 WHERE "user_id" = "created_by"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		query.SetSelect(&testItemTable.Id)
+		str, _, err = query.GetSelectQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `SELECT "i"."id" FROM "test_item" as "i" WHERE "i"."user_id" = "i"."created_by"`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success expr percent", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSet(&testItemTable.Name, Expr("%s % 2", Column(&testItemTable.Name)))
+		query.SetWhereIs(&testItemTable.Id, Expr("CAST(%s AS TEXT) LIKE '1%'", Column(&testItemTable.UserId)))
+		str, valueList, err := query.GetUpdateQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `UPDATE "test_item" SET "name" = "name" % 2 WHERE "id" = CAST("user_id" AS TEXT) LIKE '1%'`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", len(valueList))
+
+			check := `0`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error expr argument count", func(t *testing.T) {
+		testItemTable := TestItem{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetSet(&testItemTable.Name, Expr("%s + %s", Column(&testItemTable.Name)))
+		query.SetWhereIs(&testItemTable.Id, 2)
+		_, _, err := query.GetUpdateQuery()
 		{
 			target := fmt.Sprintf("%v", err)
 
-			check := `table not exist`
+			check := `expr argument count is different from the format`
 
 			if target != check {
 				t.Error("target:", target)
@@ -246,24 +1633,18 @@ func TestQueryType_GetInsertQuery(t *testing.T) {
 			}
 		}
 	})
-}
 
-func TestQueryType_GetUpdateQuery(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		item := TestItem{}
+	t.Run("success join", func(t *testing.T) {
 		testItemTable := TestItem{}
+		testUserTable := TestUser{}
 
 		query := QueryType{}
 		query.SetTable(&testItemTable)
-		query.SetSet(&testItemTable.CreatedAt, item.CreatedAt)
-		query.SetSet(&testItemTable.CreatedBy, item.CreatedBy)
-		query.SetSet(&testItemTable.UpdatedAt, item.UpdatedAt)
-		query.SetSet(&testItemTable.UpdatedBy, item.UpdatedBy)
-		query.SetSet(&testItemTable.DeletedAt, item.DeletedAt)
-		query.SetSet(&testItemTable.DeletedBy, item.DeletedBy)
-		query.SetSet(&testItemTable.Name, item.Name)
-		query.SetSet(&testItemTable.UserId, item.UserId)
-		query.SetWhereString("1 = 1")
+		query.SetJoinAs(&testUserTable, "u", &testUserTable.Id, &testItemTable.UserId)
+		query.SetJoinWhereIsNull(&testUserTable, &testUserTable.DeletedAt)
+		query.SetSet(&testItemTable.Name, 1)
+		query.SetWhereIs(&testUserTable.Name, "user")
+		query.SetWhereOrIs(&testItemTable.Id, 2)
 		str, valueList, err := query.GetUpdateQuery()
 		if err != nil {
 			t.Error(err)
@@ -273,7 +1654,7 @@ func TestQueryType_GetUpdateQuery(t *testing.T) {
 		{
 			target := str
 
-			check := `UPDATE "test_item" SET "created_at" = $1, "created_by" = $2, "updated_at" = $3, "updated_by" = $4, "deleted_at" = $5, "deleted_by" = $6, "name" = $7, "user_id" = $8 WHERE 1 = 1`
+			check := `UPDATE "test_item" SET "name" = $1 FROM "test_user" as "u" WHERE ("u"."id" = "test_item"."user_id" AND "u"."deleted_at" IS NULL) AND ("u"."name" = $2 OR "test_item"."id" = $3)`
 
 			if target != check {
 				t.Error("target:", target)
@@ -285,16 +1666,72 @@ func TestQueryType_GetUpdateQuery(t *testing.T) {
 		{
 			target := fmt.Sprintf("%v", valueList)
 
-			var checkList []interface{}
-			checkList = append(checkList, item.CreatedAt)
-			checkList = append(checkList, item.CreatedBy)
-			checkList = append(checkList, item.UpdatedAt)
-			checkList = append(checkList, item.UpdatedBy)
-			checkList = append(checkList, item.DeletedAt)
-			checkList = append(checkList, item.DeletedBy)
-			checkList = append(checkList, item.Name)
-			checkList = append(checkList, item.UserId)
-			check := fmt.Sprintf("%v", checkList)
+			check := fmt.Sprintf("%v", []interface{}{1, "user", 2})
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success join mysql", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTable(&testItemTable)
+		query.SetJoinLeft(&testUserTable, &testUserTable.Id, &testItemTable.UserId)
+		query.SetJoinWhereIs(&testUserTable, &testUserTable.Pass, "pass")
+		query.SetSet(&testItemTable.Name, 1)
+		query.SetWhereIsNull(&testUserTable.Id)
+		str, valueList, err := query.GetUpdateQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `UPDATE test_item LEFT JOIN test_user ON test_user.id = test_item.user_id AND test_user.pass = ? SET test_item.name = ? WHERE test_user.id IS NULL`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+
+		{
+			target := fmt.Sprintf("%v", valueList)
+
+			check := fmt.Sprintf("%v", []interface{}{"pass", 1})
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error join mode not supported", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		query := QueryType{}
+		query.SetTable(&testItemTable)
+		query.SetJoinLeft(&testUserTable, &testUserTable.Id, &testItemTable.UserId)
+		query.SetSet(&testItemTable.Name, 1)
+		query.SetWhereIsNull(&testUserTable.Id)
+		_, _, err := query.GetUpdateQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `join mode not supported`
 
 			if target != check {
 				t.Error("target:", target)
@@ -401,6 +1838,84 @@ func TestQueryType_GetDeleteQuery(t *testing.T) {
 		}
 	})
 
+	t.Run("success join", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		query := QueryType{}
+		query.SetTableAs(&testItemTable, "i")
+		query.SetJoin(&testUserTable, &testUserTable.Id, &testItemTable.UserId)
+		query.SetWhereIsNotNull(&testUserTable.DeletedAt)
+		str, _, err := query.GetDeleteQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `DELETE FROM "test_item" as "i" USING "test_user" WHERE "test_user"."id" = "i"."user_id" AND ("test_user"."deleted_at" IS NOT NULL)`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("success join mysql", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeMysql)
+		query.SetTableAs(&testItemTable, "i")
+		query.SetJoin(&testUserTable, &testUserTable.Id, &testItemTable.UserId)
+		query.SetWhereIsNotNull(&testUserTable.DeletedAt)
+		str, _, err := query.GetDeleteQuery()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		{
+			target := str
+
+			check := `DELETE i FROM test_item as i INNER JOIN test_user ON test_user.id = i.user_id WHERE test_user.deleted_at IS NOT NULL`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
+	t.Run("error join not supported", func(t *testing.T) {
+		testItemTable := TestItem{}
+		testUserTable := TestUser{}
+
+		query := QueryType{}
+		query.Init(nil, nil, DatabaseTypeSqlite)
+		query.SetTable(&testItemTable)
+		query.SetJoin(&testUserTable, &testUserTable.Id, &testItemTable.UserId)
+		query.SetWhereIsNotNull(&testUserTable.DeletedAt)
+		_, _, err := query.GetDeleteQuery()
+		{
+			target := fmt.Sprintf("%v", err)
+
+			check := `delete join not supported`
+
+			if target != check {
+				t.Error("target:", target)
+				t.Error("check :", check)
+				return
+			}
+		}
+	})
+
 	t.Run("error table not exist", func(t *testing.T) {
 		query := QueryType{}
 		_, _, err := query.GetDeleteQuery()
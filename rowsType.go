@@ -0,0 +1,58 @@
+package gol
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+// RowsType is the cursor of the select result. The rows are scanned one by one without buffering.
+type RowsType struct {
+	query      *QueryType
+	rows       *sql.Rows
+	columnList []string
+	scanMap    map[reflect.Type]func(rows *sql.Rows, val reflect.Value) error
+}
+
+func (rec *RowsType) Next() bool {
+	return rec.rows.Next()
+}
+
+// Scan scans the current row into dest. dest is *struct, *map[string]interface{} or *scalar.
+func (rec *RowsType) Scan(dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return errors.New("dest is not pointer")
+	}
+	destDirect := destValue.Elem()
+
+	scan, ok := rec.scanMap[destDirect.Type()]
+	if !ok {
+		var err error
+		scan, err = rec.query.makeScanFunc(destDirect.Type(), rec.columnList)
+		if err != nil {
+			return err
+		}
+
+		rec.scanMap[destDirect.Type()] = scan
+	}
+
+	err := scan(rec.rows, destDirect)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rec *RowsType) Columns() []string {
+	return rec.columnList
+}
+
+func (rec *RowsType) Err() error {
+	return rec.rows.Err()
+}
+
+func (rec *RowsType) Close() error {
+	return rec.rows.Close()
+}
@@ -0,0 +1,51 @@
+package gol
+
+// WindowType is the window of OVER (...) and WINDOW name AS (...).
+type WindowType struct {
+	Name            string
+	PartitionByList []interface{}
+	OrderByList     []*orderByType
+	Frame           string
+}
+
+func Window() *WindowType {
+	return &WindowType{}
+}
+
+// WindowName is the window defined with QueryType.SetWindow.
+func WindowName(name string) *WindowType {
+	return &WindowType{
+		Name: name,
+	}
+}
+
+func (rec *WindowType) SetPartitionBy(columnPtrList ...interface{}) {
+	rec.PartitionByList = append(rec.PartitionByList, columnPtrList...)
+}
+
+func (rec *WindowType) setOrderBy(order int, columnPtr interface{}) {
+	orderByData := &orderByType{
+		Mode:      queryModeOne,
+		Order:     order,
+		ColumnPtr: columnPtr,
+	}
+
+	rec.OrderByList = append(rec.OrderByList, orderByData)
+}
+
+func (rec *WindowType) SetOrderBy(columnPtr interface{}) {
+	rec.setOrderBy(Asc, columnPtr)
+}
+
+func (rec *WindowType) SetOrderByAsc(columnPtr interface{}) {
+	rec.setOrderBy(Asc, columnPtr)
+}
+
+func (rec *WindowType) SetOrderByDesc(columnPtr interface{}) {
+	rec.setOrderBy(Desc, columnPtr)
+}
+
+// SetFrame sets the frame clause like "ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW".
+func (rec *WindowType) SetFrame(frame string) {
+	rec.Frame = frame
+}